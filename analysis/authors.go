@@ -0,0 +1,111 @@
+package analysis
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/git"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// How many commits are searched for authors to complete
+const (
+	maxPathAuthorCommits = 500
+	maxAuthorCommits     = 5000
+)
+
+// The key of the trailer that the position is in the value of (e.g. "Co-authored-by" in
+// "Co-authored-by: "), and the range of the value, or false if it is not in one
+func trailerValueAt(msg *commit.Message, separators string, position lsp.Position) (string, lsp.Range, bool) {
+	if position.Line == 0 || position.Line >= msg.End() || msg.IsComment(position.Line) {
+		return "", lsp.Range{}, false
+	}
+
+	text := msg.Lines[position.Line]
+	if position.Character > len(text) {
+		return "", lsp.Range{}, false
+	}
+
+	key, _, separator, ok := commit.ParseTrailer(text[:position.Character], separators)
+	if !ok {
+		return "", lsp.Range{}, false
+	}
+
+	start := strings.Index(text, separator) + 1
+	start += len(text[start:]) - len(strings.TrimLeft(text[start:], " \t"))
+	return key, helper.LineRange(position.Line, min(start, position.Character), len(text)), true
+}
+
+// Whether values of the trailer are identities ("Name <email>")
+func isIdentityKey(cfg *config.Config, key string) bool {
+	return slices.ContainsFunc(cfg.Trailers.Identity, func(identity string) bool {
+		return strings.EqualFold(identity, key)
+	})
+}
+
+// The files changed by the commit: the diff in the message, or the staged files
+func touchedPaths(msg *commit.Message, root string) []string {
+	paths := []string{}
+	for _, file := range msg.Diff {
+		paths = append(paths, file.Path())
+	}
+	if len(paths) > 0 {
+		return paths
+	}
+
+	staged, err := git.StagedFiles(root)
+	if err != nil {
+		slog.Debug("unable to get staged files", "root", root, "error", err)
+	}
+	return staged
+}
+
+// Authors of the repository, with the most active authors of the touched paths first
+func rankedAuthors(msg *commit.Message, root string) []git.Author {
+	authors := []git.Author{}
+	seen := map[string]bool{}
+	add := func(more []git.Author) {
+		for _, author := range more {
+			if email := strings.ToLower(author.Email); !seen[email] {
+				seen[email] = true
+				authors = append(authors, author)
+			}
+		}
+	}
+
+	if paths := touchedPaths(msg, root); len(paths) > 0 {
+		pathAuthors, err := git.Authors(root, paths, maxPathAuthorCommits)
+		if err == nil {
+			add(pathAuthors)
+		}
+	}
+
+	all, err := git.Authors(root, nil, maxAuthorCommits)
+	if err != nil {
+		slog.Debug("unable to read authors", "root", root, "error", err)
+	}
+	add(all)
+
+	return authors
+}
+
+func identityCompletions(authors []git.Author, valueRange lsp.Range) []lsp.CompletionItem {
+	items := []lsp.CompletionItem{}
+	for i, author := range authors {
+		identity := author.Identity.String()
+		items = append(items, lsp.CompletionItem{
+			Label:    identity,
+			Detail:   fmt.Sprintf("%d commits", author.Commits),
+			Kind:     lsp.CompletionItemKindValue,
+			SortText: fmt.Sprintf("%05d", i),
+			TextEdit: &lsp.TextEdit{Range: valueRange, NewText: identity},
+		})
+	}
+
+	return items
+}
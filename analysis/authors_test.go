@@ -0,0 +1,54 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/internal/testrepo"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrailerValueAt(t *testing.T) {
+	msg := commit.ParseMessage("feat: x\n\nCo-authored-by: An\nRefs: #1\n# Co-authored-by: x")
+	at := func(line, character int) (string, lsp.Range, bool) {
+		return trailerValueAt(msg, commit.DefaultTrailerSeparators, lsp.Position{Line: line, Character: character})
+	}
+
+	key, valueRange, ok := at(2, 18)
+	assert.True(t, ok)
+	assert.Equal(t, "Co-authored-by", key)
+	assert.Equal(t, helper.LineRange(2, 16, 18), valueRange)
+
+	_, valueRange, ok = at(2, 15)
+	assert.True(t, ok)
+	assert.Equal(t, helper.LineRange(2, 15, 18), valueRange)
+
+	key, _, ok = at(3, 7)
+	assert.True(t, ok)
+	assert.Equal(t, "Refs", key)
+
+	_, _, ok = at(2, 10)
+	assert.False(t, ok)
+	_, _, ok = at(4, 19)
+	assert.False(t, ok)
+}
+
+func TestIdentityCompletion(t *testing.T) {
+	root, _ := newRepo(t)
+	testrepo.CommitAs(t, root, "Ann", "ann@example.com", "docs: x")
+
+	state := NewState(t.TempDir())
+	uri := helper.PathToURI(filepath.Join(root, ".git", "COMMIT_EDITMSG"))
+	state.OpenDocument(uri, "gitcommit", 1, "fix(api): x\n\nCo-authored-by: \n# ------------------------ >8 ------------------------\ndiff --git a/api/api.go b/api/api.go")
+
+	items := state.TextDocumentCompletion(1, uri, lsp.Position{Line: 2, Character: 16}).Result
+	require.Len(t, items, 2)
+	// The author of the changed file comes first
+	assert.Equal(t, "Test <test@example.com>", items[0].Label)
+	assert.Equal(t, &lsp.TextEdit{Range: helper.LineRange(2, 16, 16), NewText: "Test <test@example.com>"}, items[0].TextEdit)
+	assert.Equal(t, "Ann <ann@example.com>", items[1].Label)
+}
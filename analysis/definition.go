@@ -0,0 +1,130 @@
+package analysis
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/git"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// A word of 4 (the shortest abbreviation git accepts) to 40 hexadecimal digits
+var hashPattern = regexp.MustCompile(`\b[0-9a-f]{4,40}\b`)
+
+// The possible commit hash at the position, or "" if there is none
+func hashAt(msg *commit.Message, position lsp.Position) string {
+	if position.Line >= msg.End() || msg.IsComment(position.Line) {
+		return ""
+	}
+
+	text := msg.Lines[position.Line]
+	for _, match := range hashPattern.FindAllStringIndex(text, -1) {
+		if match[0] <= position.Character && position.Character <= match[1] {
+			return text[match[0]:match[1]]
+		}
+	}
+
+	return ""
+}
+
+// Directories that are never searched for scopes
+var ignoredDirectories = []string{"node_modules", "vendor"}
+
+// The files or directories that a scope refers to
+//
+// Configured paths are used if there are any. Otherwise, the directories named after the scope
+// are returned, shortest path first
+func scopePaths(root string, cfg *config.Config, scope string) []string {
+	paths := []string{}
+
+	if configured, ok := cfg.ScopePaths[scope]; ok {
+		for _, path := range configured {
+			path = filepath.Join(root, filepath.FromSlash(path))
+			if _, err := os.Stat(path); err == nil {
+				paths = append(paths, path)
+			}
+		}
+		return paths
+	}
+
+	filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() {
+			return nil
+		}
+		if path != root && (strings.HasPrefix(entry.Name(), ".") || slices.Contains(ignoredDirectories, entry.Name())) {
+			return filepath.SkipDir
+		}
+
+		if path != root && strings.EqualFold(entry.Name(), scope) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+
+	// WalkDir is depth-first, so the directory closest to the root may not be first
+	slices.SortStableFunc(paths, func(a, b string) int {
+		return strings.Count(a, string(filepath.Separator)) - strings.Count(b, string(filepath.Separator))
+	})
+
+	return paths
+}
+
+func (self *State) definition(uri string, position lsp.Position) []lsp.Location {
+	locations := []lsp.Location{}
+
+	root := repoRoot(uri)
+	if root == "" {
+		return locations
+	}
+
+	msg := commit.ParseMessage(self.document(uri).Text)
+
+	cfg := loadConfig(root)
+	msg.SplitScopes(cfg.ScopeDelimiters)
+
+	header := msg.Header
+	if position.Line == 0 && header.HasTypeScope && header.LParen != -1 {
+		for i, scopeRange := range header.ScopeRanges {
+			if !helper.RangeContains(scopeRange, position) {
+				continue
+			}
+			for _, path := range scopePaths(root, cfg, msg.Commit.Scopes[i]) {
+				locations = append(locations, lsp.Location{URI: helper.PathToURI(path)})
+			}
+			return locations
+		}
+	}
+
+	if rev := hashAt(msg, position); rev != "" {
+		if hash, ok := git.RevParse(root, rev); ok {
+			document := virtualDocument{kind: virtualShow, root: root, name: hash}
+			locations = append(locations, lsp.Location{URI: document.URI()})
+		}
+	}
+
+	return locations
+}
+
+func (self *State) Definition(id int, uri string, position lsp.Position) lsp.DefinitionResponse {
+	locations := []lsp.Location{}
+	if self.isRebaseTodo(uri) {
+		locations = self.rebaseDefinition(uri, position)
+	} else {
+		locations = self.definition(uri, position)
+	}
+
+	return lsp.DefinitionResponse{
+		Response: lsp.Response{
+			RPC: "2.0",
+			ID:  &id,
+		},
+		Result: locations,
+	}
+}
@@ -0,0 +1,66 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/internal/testrepo"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashAt(t *testing.T) {
+	msg := commit.ParseMessage("Revert \"feat: x\"\n\nThis reverts commit abc123.\n# deadbeef")
+	assert.Equal(t, "abc123", hashAt(msg, lsp.Position{Line: 2, Character: 20}))
+	assert.Equal(t, "abc123", hashAt(msg, lsp.Position{Line: 2, Character: 26}))
+	assert.Equal(t, "", hashAt(msg, lsp.Position{Line: 2, Character: 5}))
+	assert.Equal(t, "", hashAt(msg, lsp.Position{Line: 3, Character: 4}))
+}
+
+func TestScopePaths(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"lsp", "internal/lsp", "analysis", ".git/lsp", "node_modules/lsp"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0o755))
+	}
+
+	cfg := config.Default()
+	assert.Equal(t, []string{filepath.Join(root, "lsp"), filepath.Join(root, "internal/lsp")}, scopePaths(root, cfg, "lsp"))
+	assert.Empty(t, scopePaths(root, cfg, "parser"))
+
+	cfg.ScopePaths = map[string][]string{"parser": {"analysis", "missing"}}
+	assert.Equal(t, []string{filepath.Join(root, "analysis")}, scopePaths(root, cfg, "parser"))
+}
+
+// Create a repository with an "api" directory and a single commit, and return its root and the
+// hash of the commit
+func newRepo(t *testing.T) (string, string) {
+	t.Helper()
+
+	root := testrepo.Init(t)
+	testrepo.WriteFile(t, root, "api/api.go", "package api\n")
+	return root, testrepo.Commit(t, root, "feat: initial")
+}
+
+func TestDefinition(t *testing.T) {
+	root, hash := newRepo(t)
+
+	state := NewState(t.TempDir())
+	uri := helper.PathToURI(filepath.Join(root, ".git", "COMMIT_EDITMSG"))
+	state.OpenDocument(uri, "gitcommit", 1, "fix(api): x\n\nThis reverts commit "+hash[:7]+".")
+
+	assert.Equal(t, []lsp.Location{{URI: helper.PathToURI(filepath.Join(root, "api"))}}, state.definition(uri, lsp.Position{Line: 0, Character: 5}))
+
+	locations := state.definition(uri, lsp.Position{Line: 2, Character: 22})
+	require.Len(t, locations, 1)
+	content := state.TextDocumentContent(1, locations[0].URI)
+	require.Nil(t, content.Error)
+	assert.Contains(t, content.Result.Text, "commit "+hash)
+	assert.Contains(t, content.Result.Text, "feat: initial")
+
+	assert.Empty(t, state.definition(uri, lsp.Position{Line: 2, Character: 2}))
+}
@@ -0,0 +1,157 @@
+package analysis
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+func diffStats(additions int, deletions int) string {
+	return fmt.Sprintf("+%d -%d", additions, deletions)
+}
+
+// Hover contents for a position in the diff below the scissors line, or "" if there is nothing to
+// show
+func diffHover(msg *commit.Message, position lsp.Position) string {
+	if msg.Scissors == -1 || position.Line < msg.Scissors {
+		return ""
+	}
+
+	if position.Line == msg.Scissors {
+		additions, deletions := 0, 0
+		for _, file := range msg.Diff {
+			additions += file.Additions()
+			deletions += file.Deletions()
+		}
+		return fmt.Sprintf("# Staged changes\n\n- Files: %d\n- Lines: %s", len(msg.Diff), diffStats(additions, deletions))
+	}
+
+	for _, file := range msg.Diff {
+		if !helper.RangeContains(file.Range, position) {
+			continue
+		}
+
+		for _, hunk := range file.Hunks {
+			if hunk.Range.Start.Line == position.Line {
+				contents := fmt.Sprintf("# %s\n\n- Old lines: %d-%d\n- New lines: %d-%d\n- Lines: %s",
+					hunk.Header(),
+					hunk.OldStart, hunk.OldStart+max(hunk.OldLines-1, 0),
+					hunk.NewStart, hunk.NewStart+max(hunk.NewLines-1, 0),
+					diffStats(hunk.Additions, hunk.Deletions),
+				)
+				if hunk.Section != "" {
+					contents += fmt.Sprintf("\n- Section: `%s`", hunk.Section)
+				}
+				return contents
+			}
+		}
+
+		if len(file.Hunks) == 0 || position.Line < file.Hunks[0].Range.Start.Line {
+			path := file.Path()
+			if file.OldPath != file.NewPath && file.OldPath != "/dev/null" && file.NewPath != "/dev/null" {
+				path = file.OldPath + " → " + file.NewPath
+			}
+
+			return fmt.Sprintf("# %s\n\n- Hunks: %d\n- Lines: %s", path, len(file.Hunks), diffStats(file.Additions(), file.Deletions()))
+		}
+	}
+
+	return ""
+}
+
+// Whether the position is inside the parentheses of the scope in the header
+//
+// This uses the text before the position instead of the parsed header, because the header is
+// usually incomplete while the scope is being written (e.g. "feat(")
+func inScope(msg *commit.Message, position lsp.Position) bool {
+	if position.Line != 0 || position.Character > len(msg.Lines[0]) {
+		return false
+	}
+
+	before := msg.Lines[0][:position.Character]
+	lParen := strings.Index(before, "(")
+	return lParen != -1 && !strings.ContainsAny(before[lParen:], "):")
+}
+
+// The range of the scope being written at the position (between the delimiters around it), and
+// the other scopes in the parentheses
+//
+// Like inScope, this uses the text instead of the parsed header, so the position must be in the
+// scope
+func scopeAt(msg *commit.Message, delimiters string, position lsp.Position) (lsp.Range, []string) {
+	text := msg.Lines[0]
+	lParen := strings.Index(text, "(")
+
+	end := len(text)
+	if i := strings.IndexAny(text[lParen:], "):"); i != -1 {
+		end = lParen + i
+	}
+
+	start := lParen + 1
+	if i := strings.LastIndexAny(text[start:position.Character], delimiters); i != -1 {
+		start += i + 1
+	}
+	partEnd := end
+	if i := strings.IndexAny(text[position.Character:end], delimiters); i != -1 {
+		partEnd = position.Character + i
+	}
+
+	// Whitespace around the scope is not part of it
+	for start < position.Character && (text[start] == ' ' || text[start] == '\t') {
+		start++
+	}
+	for partEnd > position.Character && (text[partEnd-1] == ' ' || text[partEnd-1] == '\t') {
+		partEnd--
+	}
+
+	others := []string{}
+	isDelimiter := func(r rune) bool {
+		return strings.ContainsRune(delimiters, r)
+	}
+	for _, part := range strings.FieldsFunc(text[lParen+1:start]+" "+text[partEnd:end], isDelimiter) {
+		if part = strings.TrimSpace(part); part != "" {
+			others = append(others, part)
+		}
+	}
+
+	return helper.LineRange(0, start, partEnd), others
+}
+
+// Scopes that can be written at the position: the ticket of the branch, the configured scopes
+// and the scopes suggested by the paths of the files in the diff. Scopes that are already in the
+// parentheses are left out
+func scopeCompletions(msg *commit.Message, cfg *config.Config, position lsp.Position, ticket string) []lsp.CompletionItem {
+	scopeRange, others := scopeAt(msg, cfg.ScopeDelimiters, position)
+
+	items := []lsp.CompletionItem{}
+	add := func(scope string, detail string, kind lsp.CompletionItemKind) {
+		if slices.Contains(others, scope) || slices.ContainsFunc(items, func(item lsp.CompletionItem) bool {
+			return item.Label == scope
+		}) {
+			return
+		}
+		items = append(items, lsp.CompletionItem{
+			Label:    scope,
+			Detail:   detail,
+			Kind:     kind,
+			TextEdit: &lsp.TextEdit{Range: scopeRange, NewText: scope},
+		})
+	}
+
+	if ticket != "" {
+		add(ticket, "Ticket of the current branch", lsp.CompletionItemKindReference)
+	}
+	for _, scope := range msg.DiffScopes() {
+		add(scope, "Changed directory", lsp.CompletionItemKindModule)
+	}
+	for _, scope := range cfg.Scopes {
+		add(scope, "Configured scope", lsp.CompletionItemKindModule)
+	}
+
+	return items
+}
@@ -0,0 +1,56 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffHover(t *testing.T) {
+	msg := commit.ParseMessage(verboseMessage)
+
+	assert.Equal(t, "# Staged changes\n\n- Files: 2\n- Lines: +1 -1", diffHover(msg, lsp.Position{Line: 8}))
+	assert.Equal(t, "# api.go\n\n- Hunks: 1\n- Lines: +1 -0", diffHover(msg, lsp.Position{Line: 11, Character: 3}))
+	assert.Equal(t, "# @@ -1 +1,2 @@\n\n- Old lines: 1-1\n- New lines: 1-2\n- Lines: +1 -0", diffHover(msg, lsp.Position{Line: 14}))
+	assert.Equal(t, "", diffHover(msg, lsp.Position{Line: 15}))
+	assert.Equal(t, "", diffHover(msg, lsp.Position{Line: 2}))
+}
+
+func TestInScope(t *testing.T) {
+	msg := commit.ParseMessage("feat(ap")
+	assert.True(t, inScope(msg, lsp.Position{Line: 0, Character: 5}))
+	assert.True(t, inScope(msg, lsp.Position{Line: 0, Character: 7}))
+	assert.False(t, inScope(msg, lsp.Position{Line: 0, Character: 4}))
+
+	msg = commit.ParseMessage("feat(api): add (something)")
+	assert.False(t, inScope(msg, lsp.Position{Line: 0, Character: 17}))
+
+	assert.Equal(t, []lsp.CompletionItem{}, scopeCompletions(commit.ParseMessage("feat("), config.Default(), lsp.Position{Line: 0, Character: 5}, ""))
+}
+
+func TestScopeAt(t *testing.T) {
+	msg := commit.ParseMessage("feat(api, u/db): x")
+	scopeRange, others := scopeAt(msg, ",/", lsp.Position{Line: 0, Character: 11})
+	assert.Equal(t, helper.LineRange(0, 10, 11), scopeRange)
+	assert.Equal(t, []string{"api", "db"}, others)
+
+	scopeRange, others = scopeAt(msg, ",/", lsp.Position{Line: 0, Character: 5})
+	assert.Equal(t, helper.LineRange(0, 5, 8), scopeRange)
+	assert.Equal(t, []string{"u", "db"}, others)
+
+	// Completion after a delimiter
+	msg = commit.ParseMessage("feat(api,")
+	cfg := config.Default()
+	cfg.Scopes = []string{"api", "ui"}
+	items := scopeCompletions(msg, cfg, lsp.Position{Line: 0, Character: 9}, "")
+	assert.Equal(t, []lsp.CompletionItem{{
+		Label:    "ui",
+		Detail:   "Configured scope",
+		Kind:     lsp.CompletionItemKindModule,
+		TextEdit: &lsp.TextEdit{Range: helper.LineRange(0, 9, 9), NewText: "ui"},
+	}}, items)
+}
@@ -0,0 +1,82 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/issue"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// How many issues are completed at once
+const maxIssueCompletions = 50
+
+// The issue provider of a repository
+func (self *State) issueProvider(root string) issue.Provider {
+	index, ok := self.issues[root]
+	if !ok {
+		index = issue.OpenIndex(self.StateDir, root)
+		self.issues[root] = index
+	}
+
+	return index
+}
+
+// Completion of the ticket in the name of the current branch
+func ticketCompletion(ticket string, edit *lsp.TextEdit) lsp.CompletionItem {
+	return lsp.CompletionItem{
+		Label:    ticket,
+		Detail:   "Ticket of the current branch",
+		Kind:     lsp.CompletionItemKindReference,
+		TextEdit: edit,
+	}
+}
+
+// Issues that match what has been typed in the trailer value, replacing the whole value
+func issueCompletions(provider issue.Provider, msg *commit.Message, position lsp.Position, valueRange lsp.Range) []lsp.CompletionItem {
+	query := msg.Lines[position.Line][valueRange.Start.Character:position.Character]
+
+	items := []lsp.CompletionItem{}
+	for _, found := range provider.Search(query, maxIssueCompletions) {
+		items = append(items, lsp.CompletionItem{
+			Label:         found.ID,
+			Detail:        found.Title,
+			Documentation: found.State,
+			Kind:          lsp.CompletionItemKindReference,
+			// So that issues can be found by their title too
+			FilterText: found.ID + " " + found.Title,
+			TextEdit:   &lsp.TextEdit{Range: valueRange, NewText: found.ID},
+		})
+	}
+
+	return items
+}
+
+// Title, state and URL of the issue reference under the position, or "" if there is none or
+// the issue is unknown
+func issueHover(msg *commit.Message, cfg *config.Config, provider issue.Provider, position lsp.Position) string {
+	for _, reference := range msg.References(cfg) {
+		if reference.Kind != commit.IssueReference || !helper.RangeContains(reference.Range, position) {
+			continue
+		}
+
+		found, ok := provider.Issue(reference.Text)
+		if !ok {
+			return ""
+		}
+
+		lines := []string{fmt.Sprintf("# %s: %s", found.ID, found.Title)}
+		if found.State != "" {
+			lines = append(lines, "", fmt.Sprintf("State: %s", found.State))
+		}
+		if found.URL != "" {
+			lines = append(lines, "", found.URL)
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	return ""
+}
@@ -0,0 +1,53 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/internal/testrepo"
+	"github.com/eamonburns/git-lsp/issue"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTicketCompletion(t *testing.T) {
+	root, _ := newRepo(t)
+	testrepo.Git(t, root, "checkout", "--quiet", "-b", "feature/PROJ-123-foo")
+
+	state := NewState(t.TempDir())
+	uri := helper.PathToURI(filepath.Join(root, ".git", "COMMIT_EDITMSG"))
+	state.OpenDocument(uri, "gitcommit", 1, "fix(): x\n\nRefs: P")
+
+	items := state.TextDocumentCompletion(1, uri, lsp.Position{Line: 2, Character: 7}).Result
+	require.Len(t, items, 1)
+	assert.Equal(t, &lsp.TextEdit{Range: helper.LineRange(2, 6, 7), NewText: "PROJ-123"}, items[0].TextEdit)
+
+	items = state.TextDocumentCompletion(1, uri, lsp.Position{Line: 0, Character: 4}).Result
+	require.NotEmpty(t, items)
+	assert.Equal(t, "PROJ-123", items[0].Label)
+}
+
+func TestIssueHoverAndCompletion(t *testing.T) {
+	root, _ := newRepo(t)
+
+	state := NewState(t.TempDir())
+	_, err := issue.Import(state.StateDir, root, []issue.Issue{
+		{ID: "#12", Title: "Crash on start", State: "open", URL: "https://example.com/12"},
+		{ID: "PROJ-4", Title: "Add login"},
+	})
+	require.NoError(t, err)
+
+	uri := helper.PathToURI(filepath.Join(root, ".git", "COMMIT_EDITMSG"))
+	state.OpenDocument(uri, "gitcommit", 1, "fix: crash (#12)\n\nCloses: lo")
+
+	hover := state.Hover(1, uri, lsp.Position{Line: 0, Character: 13}).Result.Contents
+	assert.Equal(t, "# #12: Crash on start\n\nState: open\n\nhttps://example.com/12", hover)
+	assert.Contains(t, state.Hover(1, uri, lsp.Position{Line: 0, Character: 2}).Result.Contents, "Document attributes")
+
+	items := state.TextDocumentCompletion(1, uri, lsp.Position{Line: 2, Character: 10}).Result
+	require.Len(t, items, 1)
+	assert.Equal(t, "PROJ-4", items[0].Label)
+	assert.Equal(t, &lsp.TextEdit{Range: helper.LineRange(2, 8, 10), NewText: "PROJ-4"}, items[0].TextEdit)
+}
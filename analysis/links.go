@@ -0,0 +1,131 @@
+package analysis
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/git"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// URL templates used to turn references into links. See config.Config for the placeholders
+type linkTemplates struct {
+	issue  string
+	commit string
+}
+
+// The configured templates, or the ones derived from the "origin" remote
+func loadLinkTemplates(root string, cfg *config.Config) linkTemplates {
+	templates := linkTemplates{issue: cfg.IssueURL, commit: cfg.CommitURL}
+	if root == "" || (templates.issue != "" && templates.commit != "") {
+		return templates
+	}
+
+	remoteURL, err := git.RemoteURL(root, "origin")
+	if err != nil {
+		slog.Debug("unable to get origin remote", "root", root, "error", err)
+		return templates
+	}
+
+	if remote, ok := git.ParseRemote(remoteURL); ok {
+		if templates.issue == "" {
+			templates.issue = remote.IssueURL()
+		}
+		if templates.commit == "" {
+			templates.commit = remote.CommitURL()
+		}
+	}
+
+	return templates
+}
+
+var issueNumberPattern = regexp.MustCompile(`^#[0-9]+$`)
+
+// The URL of an issue reference, or "" if the template can't be used for it (e.g. "PROJ-42" with
+// a template that only has "{number}")
+func (self linkTemplates) issueURL(issue string) string {
+	if self.issue == "" {
+		return ""
+	}
+	if strings.Contains(self.issue, "{number}") && !issueNumberPattern.MatchString(issue) {
+		return ""
+	}
+
+	id := strings.TrimPrefix(issue, "#")
+	return strings.NewReplacer("{issue}", id, "{number}", id).Replace(self.issue)
+}
+
+// Links for the references in the body and footers
+//
+// resolveCommit returns the full hash of a commit that exists in the repository
+func documentLinks(msg *commit.Message, cfg *config.Config, templates linkTemplates, resolveCommit func(string) (string, bool)) []lsp.DocumentLink {
+	links := []lsp.DocumentLink{}
+
+	for _, reference := range msg.References(cfg) {
+		if reference.Range.Start.Line == 0 {
+			continue
+		}
+
+		switch reference.Kind {
+		case commit.URLReference:
+			links = append(links, lsp.DocumentLink{
+				Range:  reference.Range,
+				Target: reference.Text,
+			})
+		case commit.IssueReference:
+			if target := templates.issueURL(reference.Text); target != "" {
+				links = append(links, lsp.DocumentLink{
+					Range:   reference.Range,
+					Target:  target,
+					Tooltip: "Issue " + reference.Text,
+				})
+			}
+		case commit.CommitReference:
+			if templates.commit == "" {
+				continue
+			}
+			if hash, ok := resolveCommit(reference.Text); ok {
+				links = append(links, lsp.DocumentLink{
+					Range:   reference.Range,
+					Target:  strings.ReplaceAll(templates.commit, "{sha}", hash),
+					Tooltip: "Commit " + hash,
+				})
+			}
+		}
+	}
+
+	return links
+}
+
+func (self *State) DocumentLink(id int, uri string) lsp.DocumentLinkResponse {
+	if self.isRebaseTodo(uri) {
+		return lsp.DocumentLinkResponse{
+			Response: lsp.Response{
+				RPC: "2.0",
+				ID:  &id,
+			},
+			Result: []lsp.DocumentLink{},
+		}
+	}
+
+	msg := commit.ParseMessage(self.document(uri).Text)
+	root := repoRoot(uri)
+	cfg := loadConfig(root)
+	templates := loadLinkTemplates(root, cfg)
+
+	return lsp.DocumentLinkResponse{
+		Response: lsp.Response{
+			RPC: "2.0",
+			ID:  &id,
+		},
+		Result: documentLinks(msg, cfg, templates, func(rev string) (string, bool) {
+			if root == "" {
+				return "", false
+			}
+			return git.RevParse(root, rev)
+		}),
+	}
+}
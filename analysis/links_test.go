@@ -0,0 +1,36 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocumentLinks(t *testing.T) {
+	msg := commit.ParseMessage("fix: crash (#1)\n\nReverts 1a2b3c4 and 0000000, see https://example.com\n\nRefs: #12, PROJ-42")
+	resolveCommit := func(rev string) (string, bool) {
+		if rev == "1a2b3c4" {
+			return "1a2b3c4d5e", true
+		}
+		return "", false
+	}
+
+	assert.Equal(t, []lsp.DocumentLink{
+		{Range: helper.LineRange(2, 33, 52), Target: "https://example.com"},
+		{Range: helper.LineRange(2, 8, 15), Target: "https://github.com/o/r/commit/1a2b3c4d5e", Tooltip: "Commit 1a2b3c4d5e"},
+		{Range: helper.LineRange(4, 6, 9), Target: "https://github.com/o/r/issues/12", Tooltip: "Issue #12"},
+	}, documentLinks(msg, config.Default(), linkTemplates{
+		issue:  "https://github.com/o/r/issues/{number}",
+		commit: "https://github.com/o/r/commit/{sha}",
+	}, resolveCommit))
+
+	assert.Equal(t, []lsp.DocumentLink{
+		{Range: helper.LineRange(2, 33, 52), Target: "https://example.com"},
+		{Range: helper.LineRange(4, 6, 9), Target: "https://jira.example.com/browse/12", Tooltip: "Issue #12"},
+		{Range: helper.LineRange(4, 11, 18), Target: "https://jira.example.com/browse/PROJ-42", Tooltip: "Issue PROJ-42"},
+	}, documentLinks(msg, config.Default(), linkTemplates{issue: "https://jira.example.com/browse/{issue}"}, resolveCommit))
+}
@@ -0,0 +1,211 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/git"
+	"github.com/eamonburns/git-lsp/git/object"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/eamonburns/git-lsp/rebase"
+)
+
+// Language IDs of rebase todo lists: "gitrebase" in Neovim and Vim, "git-rebase" in VS Code
+var rebaseLanguageIDs = []string{"gitrebase", "git-rebase"}
+
+// Whether the document is the todo list of an interactive rebase, instead of a commit message
+func (self *State) isRebaseTodo(uri string) bool {
+	document := self.document(uri)
+	return slices.Contains(rebaseLanguageIDs, document.LanguageID) || path.Base(helper.URIToPath(uri)) == rebase.FileName
+}
+
+// Read a commit of the repository. The error is object.ErrNotFound if it does not exist
+func (self *State) readCommit(root string, rev string) (*object.Commit, error) {
+	var commitObject *object.Commit
+	_, err := self.readObject(root, func(reader *object.Reader) (string, error) {
+		var err error
+		commitObject, err = reader.ReadCommit(rev)
+		return "", err
+	})
+	return commitObject, err
+}
+
+func (self *State) rebaseDiagnostics(uri string) (*rebase.Todo, []commit.Diagnostic) {
+	todo := rebase.Parse(self.document(uri).Text)
+
+	root := repoRoot(uri)
+	var exists func(rev string) bool
+	if root != "" {
+		exists = func(rev string) bool {
+			// Commits are only unknown if git says so, not if it can't be run
+			_, err := self.readCommit(root, rev)
+			return !errors.Is(err, object.ErrNotFound)
+		}
+	}
+
+	return todo, rebase.Check(todo, exists)
+}
+
+// Hover contents for the command or the commit at the position, or "" if there is nothing to show
+func (self *State) rebaseHover(uri string, position lsp.Position) string {
+	todo := rebase.Parse(self.document(uri).Text)
+	line, ok := todo.LineAt(position.Line)
+	if !ok {
+		return ""
+	}
+
+	if helper.RangeContains(line.CommandRange, position) {
+		command, ok := rebase.LookupCommand(line.Command)
+		if !ok {
+			return ""
+		}
+		return fmt.Sprintf("# %s\n\n%s", command.Name, command.Description)
+	}
+
+	root := repoRoot(uri)
+	if line.Commit == "" || root == "" || !helper.RangeContains(line.CommitRange, position) {
+		return ""
+	}
+
+	commitObject, err := self.readCommit(root, line.Commit)
+	if err != nil {
+		return ""
+	}
+	return commitHover(commitObject, line.Commit, root)
+}
+
+// The message of the commit, with the files it changes
+func commitHover(commitObject *object.Commit, rev string, root string) string {
+	subject, body, _ := strings.Cut(strings.TrimRight(commitObject.Message, "\n"), "\n")
+
+	contents := fmt.Sprintf("# %s\n\n", subject)
+	if body = strings.TrimSpace(body); body != "" {
+		contents += body + "\n\n"
+	}
+
+	// "Name <email> timestamp timezone"
+	author := commitObject.Author
+	if fields := strings.Fields(author); len(fields) > 2 {
+		author = strings.Join(fields[:len(fields)-2], " ")
+	}
+	contents += fmt.Sprintf("- Author: %s", author)
+
+	stats, err := git.ShowStats(root, rev)
+	if err != nil {
+		return contents
+	}
+
+	additions, deletions := 0, 0
+	files := ""
+	for _, stat := range stats {
+		additions += stat.Additions
+		deletions += stat.Deletions
+		if stat.Binary {
+			files += fmt.Sprintf("\n- `%s`: binary", stat.Path)
+		} else {
+			files += fmt.Sprintf("\n- `%s`: %s", stat.Path, diffStats(stat.Additions, stat.Deletions))
+		}
+	}
+	contents += fmt.Sprintf("\n- Files: %d\n- Lines: %s", len(stats), diffStats(additions, deletions))
+	if files != "" {
+		contents += "\n" + files
+	}
+	return contents
+}
+
+// Commands that can be written at the position, if it is in the first word of a line
+func rebaseCompletions(todo *rebase.Todo, position lsp.Position) []lsp.CompletionItem {
+	if position.Line >= len(todo.Lines) {
+		return []lsp.CompletionItem{}
+	}
+
+	text := todo.Lines[position.Line]
+	if position.Character > len(text) || strings.HasPrefix(strings.TrimSpace(text), commit.CommentChar) {
+		return []lsp.CompletionItem{}
+	}
+	before := text[:position.Character]
+	word := strings.TrimLeft(before, " \t")
+	if strings.ContainsAny(word, " \t") {
+		return []lsp.CompletionItem{}
+	}
+
+	// The rest of the command after the position is replaced too
+	end := position.Character
+	for end < len(text) && text[end] != ' ' && text[end] != '\t' {
+		end++
+	}
+	wordRange := helper.LineRange(position.Line, len(before)-len(word), end)
+
+	items := []lsp.CompletionItem{}
+	for _, command := range rebase.Commands {
+		items = append(items, lsp.CompletionItem{
+			Label:         command.Name,
+			Detail:        command.Description,
+			Kind:          lsp.CompletionItemKindKeyword,
+			Documentation: fmt.Sprintf("Abbreviation: %s", command.Short),
+			TextEdit:      &lsp.TextEdit{Range: wordRange, NewText: command.Name},
+		})
+	}
+	return items
+}
+
+// Fixes of the diagnostics, and actions that change the commands of the lines in the range
+func (self *State) rebaseCodeActions(uri string, actionRange lsp.Range) []lsp.CodeAction {
+	actions := []lsp.CodeAction{}
+	edit := func(edits []lsp.TextEdit) *lsp.WorkspaceEdit {
+		return &lsp.WorkspaceEdit{Changes: map[string][]lsp.TextEdit{uri: edits}}
+	}
+
+	todo, diagnostics := self.rebaseDiagnostics(uri)
+	for _, diagnostic := range diagnostics {
+		if !helper.RangesOverlap(diagnostic.Range, actionRange) {
+			continue
+		}
+		for _, fix := range diagnostic.Fixes {
+			actions = append(actions, lsp.CodeAction{
+				Title:       fix.Title,
+				Kind:        lsp.CodeActionKindQuickFix,
+				Diagnostics: []lsp.Diagnostic{diagnostic.ToLspDiagnostic()},
+				Edit:        edit(fix.Edits),
+			})
+		}
+	}
+
+	for _, line := range todo.Commands {
+		if line.Line < actionRange.Start.Line || line.Line > actionRange.End.Line {
+			continue
+		}
+		for i, alternative := range line.Alternatives() {
+			actions = append(actions, lsp.CodeAction{
+				Title:       fmt.Sprintf("Change '%s' to '%s'", line.Command, alternative),
+				Kind:        lsp.CodeActionKindRefactorRewrite,
+				IsPreferred: i == 0,
+				Edit:        edit([]lsp.TextEdit{line.ReplaceCommand(alternative)}),
+			})
+		}
+	}
+
+	return actions
+}
+
+// The commit at the position, as a `git show` document
+func (self *State) rebaseDefinition(uri string, position lsp.Position) []lsp.Location {
+	locations := []lsp.Location{}
+
+	root := repoRoot(uri)
+	line, ok := rebase.Parse(self.document(uri).Text).LineAt(position.Line)
+	if root == "" || !ok || line.Commit == "" || !helper.RangeContains(line.CommitRange, position) {
+		return locations
+	}
+
+	if hash, ok := git.RevParse(root, line.Commit); ok {
+		document := virtualDocument{kind: virtualShow, root: root, name: hash}
+		locations = append(locations, lsp.Location{URI: document.URI()})
+	}
+	return locations
+}
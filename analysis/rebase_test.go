@@ -0,0 +1,54 @@
+package analysis
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebaseTodo(t *testing.T) {
+	root, hash := newRepo(t)
+
+	state := NewState(t.TempDir())
+	uri := helper.PathToURI(filepath.Join(root, ".git", "rebase-merge", "git-rebase-todo"))
+	diagnostics := state.OpenDocument(uri, "", 1, "pick "+hash[:7]+" feat: initial\npick 0000000 x\npcik\n# Commands:\n")
+	assert.True(t, state.isRebaseTodo(uri))
+
+	require.Len(t, diagnostics, 2)
+	assert.Equal(t, "Unknown commit '0000000'", diagnostics[0].Message)
+	assert.Equal(t, "Unknown command 'pcik'", diagnostics[1].Message)
+
+	hover := state.Hover(1, uri, lsp.Position{Line: 0, Character: 6}).Result.Contents
+	assert.Equal(t, "# feat: initial\n\n- Author: Test <test@example.com>\n- Files: 1\n- Lines: +1 -0\n\n- `api/api.go`: +1 -0", hover)
+	assert.Equal(t, "# pick\n\nuse commit", state.Hover(1, uri, lsp.Position{Line: 0, Character: 2}).Result.Contents)
+
+	items := state.TextDocumentCompletion(1, uri, lsp.Position{Line: 2, Character: 2}).Result
+	require.Len(t, items, 12)
+	assert.Equal(t, &lsp.TextEdit{Range: helper.LineRange(2, 0, 4), NewText: "pick"}, items[0].TextEdit)
+	assert.Empty(t, state.TextDocumentCompletion(1, uri, lsp.Position{Line: 0, Character: 8}).Result)
+
+	actions := state.CodeAction(1, uri, helper.LineRange(0, 0, 0)).Result
+	require.Len(t, actions, 5)
+	assert.Equal(t, "Change 'pick' to 'reword'", actions[0].Title)
+	assert.True(t, actions[0].IsPreferred)
+	assert.Equal(t, []lsp.TextEdit{{Range: helper.LineRange(0, 0, 4), NewText: "reword"}}, actions[0].Edit.Changes[uri])
+
+	actions = state.CodeAction(1, uri, helper.LineRange(2, 0, 0)).Result
+	require.NotEmpty(t, actions)
+	assert.Equal(t, "Replace with 'pick'", actions[0].Title)
+
+	locations := state.Definition(1, uri, lsp.Position{Line: 0, Character: 6}).Result
+	require.Len(t, locations, 1)
+	assert.Equal(t, virtualDocument{kind: virtualShow, root: root, name: hash}.URI(), locations[0].URI)
+
+	assert.Empty(t, state.SemanticTokensFull(1, uri).Result.Data)
+
+	// The language ID is enough, whatever the name of the file
+	other := helper.PathToURI(filepath.Join(root, "todo.txt"))
+	state.OpenDocument(other, "gitrebase", 1, "")
+	assert.True(t, state.isRebaseTodo(other))
+}
@@ -0,0 +1,306 @@
+package analysis
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// Semantic token types. The values are indexes into SemanticTokensLegend.TokenTypes
+const (
+	tokenType = iota
+	tokenScope
+	tokenBreaking
+	tokenDescription
+	tokenTrailerKey
+	tokenTrailerValue
+	tokenIssue
+	tokenURL
+	tokenComment
+)
+
+// Semantic token modifiers. The values are bits in the modifiers bit set, in the order of
+// SemanticTokensLegend.TokenModifiers
+const (
+	// A scope that should no longer be used
+	modifierDeprecated = 1 << iota
+	// A type that is not one of the configured types
+	modifierUnknown
+)
+
+var SemanticTokensLegend = lsp.SemanticTokensLegend{
+	TokenTypes: []string{
+		"type",      // tokenType
+		"namespace", // tokenScope
+		"operator",  // tokenBreaking
+		"string",    // tokenDescription
+		"property",  // tokenTrailerKey
+		"variable",  // tokenTrailerValue
+		"issue",     // tokenIssue
+		"url",       // tokenURL
+		"comment",   // tokenComment
+	},
+	TokenModifiers: []string{
+		"deprecated", // modifierDeprecated
+		"unknown",    // modifierUnknown
+	},
+}
+
+type semanticToken struct {
+	line      int
+	start     int
+	length    int
+	tokenType int
+	modifiers int
+}
+
+func newSemanticToken(r lsp.Range, tokenType int, modifiers int) semanticToken {
+	return semanticToken{
+		line:      r.Start.Line,
+		start:     r.Start.Character,
+		length:    r.End.Character - r.Start.Character,
+		tokenType: tokenType,
+		modifiers: modifiers,
+	}
+}
+
+func (self semanticToken) end() int {
+	return self.start + self.length
+}
+
+// Split the tokens around the overlays, so that the overlays can be added without overlapping them
+func splitAround(tokens []semanticToken, overlays []semanticToken) []semanticToken {
+	for _, overlay := range overlays {
+		split := []semanticToken{}
+		for _, token := range tokens {
+			if token.line != overlay.line || token.end() <= overlay.start || overlay.end() <= token.start {
+				split = append(split, token)
+				continue
+			}
+
+			if token.start < overlay.start {
+				before := token
+				before.length = overlay.start - token.start
+				split = append(split, before)
+			}
+			if overlay.end() < token.end() {
+				after := token
+				after.start = overlay.end()
+				after.length = token.end() - overlay.end()
+				split = append(split, after)
+			}
+		}
+		tokens = split
+	}
+
+	return append(tokens, overlays...)
+}
+
+// Get the semantic tokens of a commit message, sorted by position
+func semanticTokens(msg *commit.Message, cfg *config.Config) []semanticToken {
+	tokens := []semanticToken{}
+
+	header := msg.Header
+	if header.HasTypeScope {
+		if msg.Commit.Type != "" {
+			modifiers := 0
+			if !cfg.HasType(msg.Commit.Type) {
+				modifiers |= modifierUnknown
+			}
+			tokens = append(tokens, newSemanticToken(header.TypeRange, tokenType, modifiers))
+		}
+
+		msg.SplitScopes(cfg.ScopeDelimiters)
+		for i, scope := range msg.Commit.Scopes {
+			modifiers := 0
+			if slices.Contains(cfg.DeprecatedScopes, scope) {
+				modifiers |= modifierDeprecated
+			}
+			tokens = append(tokens, newSemanticToken(msg.Header.ScopeRanges[i], tokenScope, modifiers))
+		}
+
+		if header.Bang != -1 {
+			tokens = append(tokens, newSemanticToken(helper.LineRange(0, header.Bang, header.Bang+1), tokenBreaking, 0))
+		}
+	}
+	if msg.Commit.Description != "" {
+		tokens = append(tokens, newSemanticToken(header.DescriptionRange, tokenDescription, 0))
+	}
+
+	for _, footer := range msg.Footers {
+		tokens = append(tokens, newSemanticToken(footer.KeyRange, tokenTrailerKey, 0))
+
+		// Tokens can't span multiple lines, so there is one token per line of the value
+		for line := footer.ValueRange.Start.Line; line <= footer.ValueRange.End.Line; line++ {
+			if msg.IsComment(line) {
+				continue
+			}
+
+			text := msg.Lines[line]
+			start := len(text) - len(strings.TrimLeft(text, " \t"))
+			end := len(strings.TrimRight(text, " \t"))
+			if line == footer.ValueRange.Start.Line {
+				start = footer.ValueRange.Start.Character
+				end = footer.ValueRange.End.Character
+				if footer.ValueRange.End.Line != line {
+					end = len(text)
+				}
+			}
+			if start < end {
+				tokens = append(tokens, newSemanticToken(helper.LineRange(line, start, end), tokenTrailerValue, 0))
+			}
+		}
+	}
+
+	references := []semanticToken{}
+	for _, reference := range msg.References(cfg) {
+		switch reference.Kind {
+		case commit.IssueReference:
+			references = append(references, newSemanticToken(reference.Range, tokenIssue, 0))
+		case commit.URLReference:
+			references = append(references, newSemanticToken(reference.Range, tokenURL, 0))
+		}
+	}
+	tokens = splitAround(tokens, references)
+
+	// Comments, the scissors line, and everything after it
+	for line := 1; line < len(msg.Lines); line++ {
+		if msg.IsComment(line) && msg.Lines[line] != "" {
+			tokens = append(tokens, newSemanticToken(helper.LineRange(line, 0, len(msg.Lines[line])), tokenComment, 0))
+		}
+	}
+
+	slices.SortFunc(tokens, func(a, b semanticToken) int {
+		if a.line != b.line {
+			return a.line - b.line
+		}
+		return a.start - b.start
+	})
+
+	return tokens
+}
+
+// Encode the tokens in the relative format used by the LSP
+func encodeSemanticTokens(tokens []semanticToken) []int {
+	data := make([]int, 0, len(tokens)*5)
+
+	previousLine, previousStart := 0, 0
+	for _, token := range tokens {
+		deltaStart := token.start
+		if token.line == previousLine {
+			deltaStart = token.start - previousStart
+		}
+
+		data = append(data, token.line-previousLine, deltaStart, token.length, token.tokenType, token.modifiers)
+		previousLine, previousStart = token.line, token.start
+	}
+
+	return data
+}
+
+// The single edit that turns previous into current
+func semanticTokensEdits(previous []int, current []int) []lsp.SemanticTokensEdit {
+	prefix := 0
+	for prefix < len(previous) && prefix < len(current) && previous[prefix] == current[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(previous)-prefix && suffix < len(current)-prefix &&
+		previous[len(previous)-1-suffix] == current[len(current)-1-suffix] {
+		suffix++
+	}
+
+	if prefix == len(previous) && prefix == len(current) {
+		return []lsp.SemanticTokensEdit{}
+	}
+
+	return []lsp.SemanticTokensEdit{{
+		Start:       prefix,
+		DeleteCount: len(previous) - prefix - suffix,
+		Data:        current[prefix : len(current)-suffix],
+	}}
+}
+
+// The tokens of the document. Rebase todo lists have none, the editor highlights them
+func (self *State) documentSemanticTokens(uri string) []semanticToken {
+	if self.isRebaseTodo(uri) {
+		return []semanticToken{}
+	}
+
+	return semanticTokens(commit.ParseMessage(self.document(uri).Text), loadConfig(repoRoot(uri)))
+}
+
+// Compute the tokens for the whole document, and remember them so that the next request can be
+// answered with a delta
+func (self *State) fullSemanticTokens(uri string) lsp.SemanticTokens {
+	document := self.document(uri)
+
+	document.semanticTokensResultCount++
+	document.semanticTokensResultID = strconv.Itoa(document.semanticTokensResultCount)
+	document.semanticTokens = encodeSemanticTokens(self.documentSemanticTokens(uri))
+
+	return lsp.SemanticTokens{
+		ResultID: document.semanticTokensResultID,
+		Data:     document.semanticTokens,
+	}
+}
+
+func (self *State) SemanticTokensFull(id int, uri string) lsp.SemanticTokensResponse {
+	return lsp.SemanticTokensResponse{
+		Response: lsp.Response{
+			RPC: "2.0",
+			ID:  &id,
+		},
+		Result: self.fullSemanticTokens(uri),
+	}
+}
+
+func (self *State) SemanticTokensFullDelta(id int, uri string, previousResultID string) lsp.SemanticTokensDeltaResponse {
+	response := lsp.SemanticTokensDeltaResponse{
+		Response: lsp.Response{
+			RPC: "2.0",
+			ID:  &id,
+		},
+	}
+
+	document := self.document(uri)
+	if previousResultID == "" || previousResultID != document.semanticTokensResultID {
+		// The client has a result we don't know about anymore, so it gets all tokens
+		response.Result = self.fullSemanticTokens(uri)
+		return response
+	}
+
+	previous := document.semanticTokens
+	current := self.fullSemanticTokens(uri)
+	response.Result = lsp.SemanticTokensDelta{
+		ResultID: current.ResultID,
+		Edits:    semanticTokensEdits(previous, current.Data),
+	}
+
+	return response
+}
+
+func (self *State) SemanticTokensRange(id int, uri string, tokensRange lsp.Range) lsp.SemanticTokensResponse {
+	tokens := []semanticToken{}
+	for _, token := range self.documentSemanticTokens(uri) {
+		if helper.RangesOverlap(helper.LineRange(token.line, token.start, token.end()), tokensRange) {
+			tokens = append(tokens, token)
+		}
+	}
+
+	return lsp.SemanticTokensResponse{
+		Response: lsp.Response{
+			RPC: "2.0",
+			ID:  &id,
+		},
+		Result: lsp.SemanticTokens{
+			Data: encodeSemanticTokens(tokens),
+		},
+	}
+}
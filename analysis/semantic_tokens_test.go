@@ -0,0 +1,53 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSemanticTokens(t *testing.T) {
+	cfg := config.Default()
+	cfg.DeprecatedScopes = []string{"old"}
+
+	msg := commit.ParseMessage("feta(old)!: see #12\n\nBody https://example.com\n\nRefs: #3, ABC-4\n  more\n# comment")
+	assert.Equal(t, []semanticToken{
+		{line: 0, start: 0, length: 4, tokenType: tokenType, modifiers: modifierUnknown},
+		{line: 0, start: 5, length: 3, tokenType: tokenScope, modifiers: modifierDeprecated},
+		{line: 0, start: 9, length: 1, tokenType: tokenBreaking},
+		{line: 0, start: 12, length: 4, tokenType: tokenDescription},
+		{line: 0, start: 16, length: 3, tokenType: tokenIssue},
+		{line: 2, start: 5, length: 19, tokenType: tokenURL},
+		{line: 4, start: 0, length: 4, tokenType: tokenTrailerKey},
+		{line: 4, start: 6, length: 2, tokenType: tokenIssue},
+		{line: 4, start: 8, length: 2, tokenType: tokenTrailerValue},
+		{line: 4, start: 10, length: 5, tokenType: tokenIssue},
+		{line: 5, start: 2, length: 4, tokenType: tokenTrailerValue},
+		{line: 6, start: 0, length: 9, tokenType: tokenComment},
+	}, semanticTokens(msg, cfg))
+}
+
+func TestEncodeSemanticTokens(t *testing.T) {
+	assert.Equal(t, []int{
+		0, 0, 4, tokenType, 0,
+		0, 5, 3, tokenScope, modifierDeprecated,
+		2, 3, 5, tokenComment, 0,
+	}, encodeSemanticTokens([]semanticToken{
+		{line: 0, start: 0, length: 4, tokenType: tokenType},
+		{line: 0, start: 5, length: 3, tokenType: tokenScope, modifiers: modifierDeprecated},
+		{line: 2, start: 3, length: 5, tokenType: tokenComment},
+	}))
+}
+
+func TestSemanticTokensEdits(t *testing.T) {
+	assert.Equal(t, []lsp.SemanticTokensEdit{}, semanticTokensEdits([]int{1, 2, 3}, []int{1, 2, 3}))
+	assert.Equal(t, []lsp.SemanticTokensEdit{
+		{Start: 1, DeleteCount: 1, Data: []int{5, 6}},
+	}, semanticTokensEdits([]int{1, 2, 3}, []int{1, 5, 6, 3}))
+	assert.Equal(t, []lsp.SemanticTokensEdit{
+		{Start: 2, DeleteCount: 1, Data: []int{}},
+	}, semanticTokensEdits([]int{1, 2, 3}, []int{1, 2}))
+}
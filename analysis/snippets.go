@@ -0,0 +1,170 @@
+package analysis
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/git"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// Escape text so that it is inserted as it is in a snippet
+func escapeSnippet(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `$`, `\$`, `}`, `\}`).Replace(text)
+}
+
+// A tab stop with a choice of values (e.g. "${1|feat,fix|}")
+func choiceTabStop(number int, values []string) string {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		escaped[i] = strings.NewReplacer(`\`, `\\`, `,`, `\,`, `|`, `\|`).Replace(value)
+	}
+	return fmt.Sprintf("${%d|%s|}", number, strings.Join(escaped, ","))
+}
+
+// Everything the template and the footer snippets are filled in with
+type snippetContext struct {
+	cfg *config.Config
+	// Ticket in the name of the current branch, or ""
+	ticket string
+	// The committer, or false if it is unknown
+	committer      git.Identity
+	knownCommitter bool
+}
+
+func newSnippetContext(repo commit.Repo, cfg *config.Config) snippetContext {
+	context := snippetContext{cfg: cfg, ticket: commit.BranchTicket(repo, cfg)}
+	if repo.Root != "" {
+		context.committer, context.knownCommitter = git.Committer(repo.Root)
+	}
+	return context
+}
+
+// The footers that the configuration requires, with tab stops starting at the number
+func (self snippetContext) requiredFooters(number int) []string {
+	footers := []string{}
+	if self.cfg.IssueReference.Required {
+		ticket := self.ticket
+		if ticket == "" {
+			ticket = "ticket"
+		}
+		footers = append(footers, fmt.Sprintf("%s: ${%d:%s}", commit.RefsKey, number, escapeSnippet(ticket)))
+	}
+	if self.cfg.RequireSignOff && self.knownCommitter {
+		footers = append(footers, escapeSnippet(fmt.Sprintf("%s: %s", commit.SignOffKey, self.committer)))
+	}
+	return footers
+}
+
+var templatePlaceholderPattern = regexp.MustCompile(`\{(type|scope|description|body|footers)\}`)
+
+// The configured template as a snippet, with a tab stop for each placeholder
+func (self snippetContext) messageTemplate() string {
+	number := 0
+	nextTabStop := func() int {
+		number++
+		return number
+	}
+
+	snippet := ""
+	last := 0
+	for _, match := range templatePlaceholderPattern.FindAllStringSubmatchIndex(self.cfg.Template, -1) {
+		snippet += escapeSnippet(self.cfg.Template[last:match[0]])
+		last = match[1]
+
+		switch self.cfg.Template[match[2]:match[3]] {
+		case "type":
+			types := []string{}
+			for _, commitType := range self.cfg.Types {
+				types = append(types, commitType.Name)
+			}
+			snippet += choiceTabStop(nextTabStop(), types)
+		case "scope":
+			if len(self.cfg.Scopes) > 0 {
+				snippet += choiceTabStop(nextTabStop(), self.cfg.Scopes)
+			} else {
+				snippet += fmt.Sprintf("${%d:scope}", nextTabStop())
+			}
+		case "description":
+			snippet += fmt.Sprintf("${%d:description}", nextTabStop())
+		case "body":
+			snippet += fmt.Sprintf("${%d:body}", nextTabStop())
+		case "footers":
+			footers := self.requiredFooters(number + 1)
+			if len(footers) > 0 && strings.Contains(footers[0], "${") {
+				nextTabStop()
+			}
+			snippet += strings.Join(footers, "\n")
+		}
+	}
+	snippet += escapeSnippet(self.cfg.Template[last:])
+
+	// There may be no footers
+	return strings.TrimRight(snippet, "\n") + "\n"
+}
+
+// Completion of the whole message from the template, replacing the first line
+func templateCompletion(msg *commit.Message, context snippetContext) lsp.CompletionItem {
+	return lsp.CompletionItem{
+		Label:            "Commit message template",
+		Detail:           "Template from " + config.FileName,
+		Kind:             lsp.CompletionItemKindSnippet,
+		TextEdit:         &lsp.TextEdit{Range: helper.LineRange(0, 0, len(msg.Lines[0])), NewText: context.messageTemplate()},
+		InsertTextFormat: lsp.InsertTextFormatSnippet,
+	}
+}
+
+// The range of the footer key being written at the position (from the start of the line), or
+// false if the text before the position is not the start of a footer
+func footerKeyAt(msg *commit.Message, position lsp.Position) (lsp.Range, bool) {
+	if position.Line == 0 || position.Line >= msg.End() || msg.IsComment(position.Line) {
+		return lsp.Range{}, false
+	}
+
+	text := msg.Lines[position.Line]
+	if position.Character > len(text) || strings.ContainsAny(text[:position.Character], " \t:#") {
+		return lsp.Range{}, false
+	}
+	return helper.LineRange(position.Line, 0, position.Character), true
+}
+
+// Snippets for the breaking change footer and common trailers
+func footerSnippets(keyRange lsp.Range, context snippetContext) []lsp.CompletionItem {
+	signOff := fmt.Sprintf("%s: ${1:Name} <${2:email}>", commit.SignOffKey)
+	if context.knownCommitter {
+		signOff = escapeSnippet(fmt.Sprintf("%s: %s", commit.SignOffKey, context.committer))
+	}
+	ticket := context.ticket
+	if ticket == "" {
+		ticket = "ticket"
+	}
+
+	snippets := []struct {
+		label   string
+		snippet string
+	}{
+		{commit.BreakingChangeKey, commit.BreakingChangeKey + ": ${1:description}"},
+		{commit.RefsKey, fmt.Sprintf("%s: ${1:%s}", commit.RefsKey, escapeSnippet(ticket))},
+		{"Closes", "Closes: ${1:issue}"},
+		{commit.CoAuthorKey, commit.CoAuthorKey + ": ${1:Name} <${2:email}>"},
+		{commit.SignOffKey, signOff},
+		{"Reviewed-by", "Reviewed-by: ${1:Name} <${2:email}>"},
+	}
+
+	items := []lsp.CompletionItem{}
+	for _, snippet := range snippets {
+		items = append(items, lsp.CompletionItem{
+			Label:            snippet.label,
+			Detail:           "Footer",
+			Kind:             lsp.CompletionItemKindSnippet,
+			TextEdit:         &lsp.TextEdit{Range: keyRange, NewText: snippet.snippet},
+			InsertTextFormat: lsp.InsertTextFormatSnippet,
+		})
+	}
+
+	return items
+}
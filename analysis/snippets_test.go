@@ -0,0 +1,63 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/git"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageTemplate(t *testing.T) {
+	cfg := config.Default()
+	cfg.Types = []config.CommitType{{Name: "feat"}, {Name: "fix"}}
+	context := snippetContext{cfg: cfg}
+	assert.Equal(t, "${1|feat,fix|}(${2:scope}): ${3:description}\n\n${4:body}\n", context.messageTemplate())
+
+	cfg.Scopes = []string{"api", "ui"}
+	cfg.Template = "{type}({scope}): {description} $5\n\n{footers}"
+	cfg.IssueReference.Required = true
+	cfg.RequireSignOff = true
+	context = snippetContext{
+		cfg:            cfg,
+		ticket:         "PROJ-1",
+		committer:      git.Identity{Name: "Test", Email: "test@example.com"},
+		knownCommitter: true,
+	}
+	assert.Equal(t, "${1|feat,fix|}(${2|api,ui|}): ${3:description} \\$5\n\n"+
+		"Refs: ${4:PROJ-1}\nSigned-off-by: Test <test@example.com>\n", context.messageTemplate())
+}
+
+func TestSnippetCompletion(t *testing.T) {
+	root, _ := newRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(root, config.FileName), []byte(`{"types": ["feat"]}`), 0o644))
+
+	state := NewState(t.TempDir())
+	uri := helper.PathToURI(filepath.Join(root, ".git", "COMMIT_EDITMSG"))
+	state.OpenDocument(uri, "gitcommit", 1, "\n# Please enter the commit message for your changes.\n")
+
+	items := state.TextDocumentCompletion(1, uri, lsp.Position{Line: 0, Character: 0}).Result
+	require.Len(t, items, 1)
+	assert.Equal(t, lsp.CompletionItemKindSnippet, items[0].Kind)
+	assert.Equal(t, lsp.InsertTextFormatSnippet, items[0].InsertTextFormat)
+	assert.Equal(t, &lsp.TextEdit{
+		Range:   helper.LineRange(0, 0, 0),
+		NewText: "${1|feat|}(${2:scope}): ${3:description}\n\n${4:body}\n",
+	}, items[0].TextEdit)
+
+	state.UpdateDocument(uri, 2, "feat: x\n\nBRE")
+	items = state.TextDocumentCompletion(1, uri, lsp.Position{Line: 2, Character: 3}).Result
+	require.NotEmpty(t, items)
+	assert.Equal(t, commit.BreakingChangeKey, items[0].Label)
+	assert.Equal(t, &lsp.TextEdit{Range: helper.LineRange(2, 0, 3), NewText: "BREAKING CHANGE: ${1:description}"}, items[0].TextEdit)
+
+	// Not at the start of a footer
+	state.UpdateDocument(uri, 3, "feat: x\n\nSome text")
+	assert.Empty(t, state.TextDocumentCompletion(1, uri, lsp.Position{Line: 2, Character: 9}).Result)
+}
@@ -2,21 +2,166 @@ package analysis
 
 import (
 	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
 
 	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/external"
+	"github.com/eamonburns/git-lsp/git/object"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/issue"
 	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/eamonburns/git-lsp/rebase"
 )
 
 type State struct {
-	Documents map[string]string
+	Documents map[string]*Document
+
+	// Directory where the server can write files (e.g. the issue indexes)
+	StateDir string
+
+	// Object readers of the repositories, by root
+	readers map[string]*object.Reader
+	// Issue indexes of the repositories, by root
+	issues map[string]*issue.Index
+
+	// Called with new diagnostics of a document when external rules finish after the request
+	// that started them. External rules run synchronously if this is nil
+	Publish func(uri string, diagnostics []lsp.Diagnostic)
+	// Held while the state is used, by the server for each message and by the external rules
+	// when they finish
+	mutex *sync.Mutex
+}
+
+type Document struct {
+	Text string
+	// Language ID given by the client (e.g. "gitcommit" or "gitrebase")
+	LanguageID string
+	// Increases after each change
+	Version int
+
+	// Diagnostics reported by external rules, and the version of the document they are for
+	// External rules can be slow, so they are only run once per version
+	externalVersion     int
+	externalDiagnostics []commit.Diagnostic
+	// Whether external rules are running in the background for this document
+	externalRunning bool
+
+	// The last semantic tokens sent to the client, so that the next request can be answered
+	// with a delta
+	semanticTokensResultCount int
+	semanticTokensResultID    string
+	semanticTokens            []int
+}
+
+func NewState(stateDir string) State {
+	return State{
+		Documents: make(map[string]*Document),
+		StateDir:  stateDir,
+		readers:   make(map[string]*object.Reader),
+		issues:    make(map[string]*issue.Index),
+		mutex:     &sync.Mutex{},
+	}
+}
+
+func (self *State) Lock() {
+	self.mutex.Lock()
+}
+
+func (self *State) Unlock() {
+	self.mutex.Unlock()
+}
+
+// The open document with the given URI, or an empty document if it is not open
+func (self *State) document(uri string) *Document {
+	if document, ok := self.Documents[uri]; ok {
+		return document
+	}
+
+	return &Document{}
+}
+
+func repoRoot(uri string) string {
+	return helper.RepoRoot(helper.URIToPath(uri))
 }
 
-func NewState() State {
-	return State{Documents: make(map[string]string)}
+func loadConfig(root string) *config.Config {
+	cfg, err := config.Load(root)
+	if err != nil {
+		slog.Error("unable to load config", "root", root, "error", err)
+	}
+
+	return cfg
+}
+
+// Parse the document, and check it with all registered rules and external rules
+// Diagnostics of rules that are disabled by suppression comments are removed
+func (self *State) diagnose(uri string) (*commit.Message, []commit.Diagnostic) {
+	document := self.document(uri)
+	root := repoRoot(uri)
+	cfg := loadConfig(root)
+
+	msg := commit.ParseMessage(document.Text)
+	diagnostics := commit.Check(&commit.Context{
+		Message: msg,
+		Repo:    commit.Repo{Root: root},
+		Config:  cfg,
+	})
+
+	if document.externalDiagnostics == nil || document.externalVersion != document.Version {
+		self.runExternalRules(uri, document, cfg, root, msg)
+	}
+	diagnostics = append(diagnostics, document.externalDiagnostics...)
+
+	return msg, commit.Suppress(msg, diagnostics, external.RuleIDs(cfg.ExternalRules))
+}
+
+// Run the external rules on the current version of the document
+//
+// With Publish, the rules run in the background, so that slow commands do not block the server.
+// Until they finish, the document keeps the diagnostics of the previous run. At most one run per
+// document is in progress: if the document changed while it ran, the next one starts when its
+// diagnostics are published
+func (self *State) runExternalRules(uri string, document *Document, cfg *config.Config, root string, msg *commit.Message) {
+	if self.Publish == nil || len(cfg.ExternalRules) == 0 {
+		document.externalVersion = document.Version
+		document.externalDiagnostics = external.RunAll(cfg.ExternalRules, root, document.Text, msg)
+		return
+	}
+	if document.externalRunning {
+		return
+	}
+
+	document.externalRunning = true
+	version := document.Version
+	text := document.Text
+	go func() {
+		diagnostics := external.RunAll(cfg.ExternalRules, root, text, msg)
+
+		self.Lock()
+		defer self.Unlock()
+
+		document.externalRunning = false
+		if self.Documents[uri] != document {
+			// The document was closed or opened again
+			return
+		}
+		document.externalVersion = version
+		document.externalDiagnostics = diagnostics
+		self.Publish(uri, self.getDiagnosticsForFile(uri))
+	}()
 }
 
-func getDiagnosticsForFile(text string) []lsp.Diagnostic {
-	_, commitDiagnostics := commit.Parse(text)
+func (self *State) getDiagnosticsForFile(uri string) []lsp.Diagnostic {
+	var commitDiagnostics []commit.Diagnostic
+	if self.isRebaseTodo(uri) {
+		_, commitDiagnostics = self.rebaseDiagnostics(uri)
+	} else {
+		_, commitDiagnostics = self.diagnose(uri)
+	}
 
 	lspDiagnostics := make([]lsp.Diagnostic, len(commitDiagnostics))
 
@@ -27,20 +172,46 @@ func getDiagnosticsForFile(text string) []lsp.Diagnostic {
 	return lspDiagnostics
 }
 
-func (self *State) OpenDocument(uri string, text string) []lsp.Diagnostic {
-	self.Documents[uri] = text
+func (self *State) OpenDocument(uri string, languageID string, version int, text string) []lsp.Diagnostic {
+	self.Documents[uri] = &Document{
+		Text:       text,
+		LanguageID: languageID,
+		Version:    version,
+	}
 
-	return getDiagnosticsForFile(text)
+	return self.getDiagnosticsForFile(uri)
 }
 
-func (self *State) UpdateDocument(uri string, text string) []lsp.Diagnostic {
-	self.Documents[uri] = text
+func (self *State) UpdateDocument(uri string, version int, text string) []lsp.Diagnostic {
+	document, ok := self.Documents[uri]
+	if !ok {
+		return self.OpenDocument(uri, "", version, text)
+	}
+
+	document.Text = text
+	document.Version = version
 
-	return getDiagnosticsForFile(text)
+	return self.getDiagnosticsForFile(uri)
 }
 
 func (self *State) Hover(id int, uri string, position lsp.Position) lsp.HoverResponse {
-	document := self.Documents[uri]
+	document := self.document(uri)
+
+	root := repoRoot(uri)
+	msg := commit.ParseMessage(document.Text)
+
+	contents := ""
+	if self.isRebaseTodo(uri) {
+		contents = self.rebaseHover(uri, position)
+	} else {
+		contents = diffHover(msg, position)
+		if contents == "" && root != "" {
+			contents = issueHover(msg, loadConfig(root), self.issueProvider(root), position)
+		}
+	}
+	if contents == "" {
+		contents = fmt.Sprintf("# Document attributes\n\n- URI: %s\n- Characters: %d", uri, len(document.Text))
+	}
 
 	return lsp.HoverResponse{
 		Response: lsp.Response{
@@ -48,26 +219,59 @@ func (self *State) Hover(id int, uri string, position lsp.Position) lsp.HoverRes
 			ID:  &id,
 		},
 		Result: lsp.HoverResult{
-			Contents: fmt.Sprintf("# Document attributes\n\n- URI: %s\n- Characters: %d", uri, len(document)),
+			Contents: contents,
 		},
 	}
 }
 
+// The characters that trigger completion: the "(" before the scope, and the scope delimiters
+// configured in the repository of the workspace
+func CompletionTriggerCharacters(rootURI string) []string {
+	root := ""
+	if rootURI != "" {
+		root = helper.RepoRoot(filepath.Join(helper.URIToPath(rootURI), config.FileName))
+	}
+
+	characters := []string{"("}
+	for _, delimiter := range loadConfig(root).ScopeDelimiters {
+		characters = append(characters, string(delimiter))
+	}
+	return characters
+}
+
 func (self *State) TextDocumentCompletion(id int, uri string, position lsp.Position) lsp.CompletionResponse {
-	// TODO: Get completions
-	items := []lsp.CompletionItem{
-		{
-			Label:         "NeoVim (BTW)",
-			Detail:        "Very cool editor",
-			Documentation: "Fun to watch in videos :)",
-			Kind:          lsp.CompletionItemKindText,
-		},
-		{
-			Label:         "Position",
-			Detail:        fmt.Sprintf("line: %d, character: %d", position.Line, position.Character),
-			Documentation: "Current position in the file",
-			Kind:          lsp.CompletionItemKindText,
-		},
+	if self.isRebaseTodo(uri) {
+		return lsp.CompletionResponse{
+			Response: lsp.Response{
+				RPC: "2.0",
+				ID:  &id,
+			},
+			Result: rebaseCompletions(rebase.Parse(self.document(uri).Text), position),
+		}
+	}
+
+	msg := commit.ParseMessage(self.document(uri).Text)
+	root := repoRoot(uri)
+	repo := commit.Repo{Root: root}
+
+	cfg := loadConfig(root)
+
+	items := []lsp.CompletionItem{}
+	if inScope(msg, position) {
+		items = scopeCompletions(msg, cfg, position, commit.BranchTicket(repo, cfg))
+	} else if key, valueRange, ok := trailerValueAt(msg, commit.TrailerSeparators(repo), position); ok && root != "" {
+		if isIdentityKey(cfg, key) {
+			items = identityCompletions(rankedAuthors(msg, root), valueRange)
+		} else {
+			if ticket := commit.BranchTicket(repo, cfg); strings.EqualFold(key, commit.RefsKey) && ticket != "" {
+				items = append(items, ticketCompletion(ticket, &lsp.TextEdit{Range: valueRange, NewText: ticket}))
+			}
+			items = append(items, issueCompletions(self.issueProvider(root), msg, position, valueRange)...)
+		}
+	} else if position.Line == 0 && msg.IsEmpty() {
+		items = append(items, templateCompletion(msg, newSnippetContext(repo, cfg)))
+	} else if keyRange, ok := footerKeyAt(msg, position); ok {
+		items = footerSnippets(keyRange, newSnippetContext(repo, cfg))
 	}
 
 	return lsp.CompletionResponse{
@@ -78,3 +282,48 @@ func (self *State) TextDocumentCompletion(id int, uri string, position lsp.Posit
 		Result: items,
 	}
 }
+
+func (self *State) CodeAction(id int, uri string, actionRange lsp.Range) lsp.CodeActionResponse {
+	if self.isRebaseTodo(uri) {
+		return lsp.CodeActionResponse{
+			Response: lsp.Response{
+				RPC: "2.0",
+				ID:  &id,
+			},
+			Result: self.rebaseCodeActions(uri, actionRange),
+		}
+	}
+
+	actions := []lsp.CodeAction{}
+
+	msg, diagnostics := self.diagnose(uri)
+	for _, diagnostic := range diagnostics {
+		if !helper.RangesOverlap(diagnostic.Range, actionRange) {
+			continue
+		}
+
+		fixes := diagnostic.Fixes
+		if diagnostic.Type != commit.UnknownSuppressedRuleWarning && diagnostic.Type != commit.UnusedSuppressionWarning {
+			fixes = append(fixes, msg.SuppressionFix(diagnostic.Type))
+		}
+
+		for _, fix := range fixes {
+			actions = append(actions, lsp.CodeAction{
+				Title:       fix.Title,
+				Kind:        lsp.CodeActionKindQuickFix,
+				Diagnostics: []lsp.Diagnostic{diagnostic.ToLspDiagnostic()},
+				Edit: &lsp.WorkspaceEdit{
+					Changes: map[string][]lsp.TextEdit{uri: fix.Edits},
+				},
+			})
+		}
+	}
+
+	return lsp.CodeActionResponse{
+		Response: lsp.Response{
+			RPC: "2.0",
+			ID:  &id,
+		},
+		Result: actions,
+	}
+}
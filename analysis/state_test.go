@@ -0,0 +1,50 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalRulesInBackground(t *testing.T) {
+	root, _ := newRepo(t)
+	script := filepath.Join(t.TempDir(), "rule.sh")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\nsleep 0.2\necho '[{\"message\": \"slow\"}]'\n"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, config.FileName), []byte(`{"externalRules": [{"id": "team/slow", "command": ["`+script+`"]}]}`), 0o644))
+
+	published := make(chan []lsp.Diagnostic, 1)
+	state := NewState(t.TempDir())
+	state.Publish = func(uri string, diagnostics []lsp.Diagnostic) {
+		published <- diagnostics
+	}
+	uri := helper.PathToURI(filepath.Join(root, ".git", "COMMIT_EDITMSG"))
+
+	state.Lock()
+	start := time.Now()
+	assert.Empty(t, state.OpenDocument(uri, "gitcommit", 1, "feat: x"))
+	assert.Less(t, time.Since(start), 200*time.Millisecond)
+	state.Unlock()
+
+	select {
+	case diagnostics := <-published:
+		require.Len(t, diagnostics, 1)
+		assert.Equal(t, "slow", diagnostics[0].Message)
+	case <-time.After(5 * time.Second):
+		t.Fatal("diagnostics of the external rule were not published")
+	}
+}
+
+func TestCompletionTriggerCharacters(t *testing.T) {
+	assert.Equal(t, []string{"(", ",", "/"}, CompletionTriggerCharacters(""))
+
+	root, _ := newRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(root, config.FileName), []byte(`{"scopeDelimiters": ",+"}`), 0o644))
+	assert.Equal(t, []string{"(", ",", "+"}, CompletionTriggerCharacters(helper.PathToURI(root)))
+}
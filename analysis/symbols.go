@@ -0,0 +1,192 @@
+package analysis
+
+import (
+	"unicode/utf8"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// Maximum length of symbol names (in characters) that are taken from the text of the message
+const maxSymbolNameLength = 50
+
+func symbolName(text string) string {
+	if utf8.RuneCountInString(text) <= maxSymbolNameLength {
+		return text
+	}
+
+	// Cut at a character boundary, so that the name stays valid UTF-8
+	count := 0
+	for i := range text {
+		if count == maxSymbolNameLength-3 {
+			return text[:i] + "..."
+		}
+		count++
+	}
+	return text
+}
+
+func newSymbol(name string, detail string, kind lsp.SymbolKind, symbolRange lsp.Range) lsp.DocumentSymbol {
+	return lsp.DocumentSymbol{
+		Name:           name,
+		Detail:         detail,
+		Kind:           kind,
+		Range:          symbolRange,
+		SelectionRange: symbolRange,
+	}
+}
+
+// Ranges of the consecutive comment lines before the scissors line
+func commentBlocks(msg *commit.Message) []lsp.Range {
+	blocks := []lsp.Range{}
+	for _, line := range msg.Comments {
+		end := lsp.Position{Line: line, Character: len(msg.Lines[line])}
+		if len(blocks) > 0 && blocks[len(blocks)-1].End.Line == line-1 {
+			blocks[len(blocks)-1].End = end
+		} else {
+			blocks = append(blocks, lsp.Range{Start: lsp.Position{Line: line, Character: 0}, End: end})
+		}
+	}
+
+	return blocks
+}
+
+// Range from the scissors line to the end of the document
+func diffSectionRange(msg *commit.Message) lsp.Range {
+	last := len(msg.Lines) - 1
+	return lsp.Range{
+		Start: lsp.Position{Line: msg.Scissors, Character: 0},
+		End:   lsp.Position{Line: last, Character: len(msg.Lines[last])},
+	}
+}
+
+func documentSymbols(msg *commit.Message) []lsp.DocumentSymbol {
+	symbols := []lsp.DocumentSymbol{}
+
+	header := newSymbol("Header", msg.Header.Text, lsp.SymbolKindStruct, helper.LineRange(0, 0, len(msg.Header.Text)))
+	if msg.Commit.Type != "" {
+		header.Children = append(header.Children, newSymbol(msg.Commit.Type, "type", lsp.SymbolKindTypeParameter, msg.Header.TypeRange))
+	}
+	if msg.Commit.Scope != "" {
+		header.Children = append(header.Children, newSymbol(msg.Commit.Scope, "scope", lsp.SymbolKindNamespace, msg.Header.ScopeRange))
+	}
+	if msg.Commit.Description != "" {
+		header.Children = append(header.Children, newSymbol(symbolName(msg.Commit.Description), "description", lsp.SymbolKindString, msg.Header.DescriptionRange))
+	}
+	symbols = append(symbols, header)
+
+	if len(msg.Body) > 0 {
+		body := newSymbol("Body", "", lsp.SymbolKindObject, lsp.Range{
+			Start: msg.Body[0].Start,
+			End:   msg.Body[len(msg.Body)-1].End,
+		})
+		for _, paragraph := range msg.Body {
+			name := symbolName(msg.Lines[paragraph.Start.Line])
+			body.Children = append(body.Children, newSymbol(name, "paragraph", lsp.SymbolKindString, paragraph))
+		}
+		symbols = append(symbols, body)
+	}
+
+	if len(msg.Footers) > 0 {
+		footers := newSymbol("Footers", "", lsp.SymbolKindObject, lsp.Range{
+			Start: msg.Footers[0].Range.Start,
+			End:   msg.Footers[len(msg.Footers)-1].Range.End,
+		})
+		for _, footer := range msg.Footers {
+			symbol := newSymbol(footer.Key, symbolName(footer.Value), lsp.SymbolKindProperty, footer.Range)
+			symbol.SelectionRange = footer.KeyRange
+			footers.Children = append(footers.Children, symbol)
+		}
+		symbols = append(symbols, footers)
+	}
+
+	for _, block := range commentBlocks(msg) {
+		symbols = append(symbols, newSymbol("Comments", "", lsp.SymbolKindNull, block))
+	}
+
+	if msg.Scissors != -1 {
+		diff := newSymbol("Diff", "", lsp.SymbolKindModule, diffSectionRange(msg))
+		diff.SelectionRange = helper.LineRange(msg.Scissors, 0, len(msg.Lines[msg.Scissors]))
+		for _, file := range msg.Diff {
+			symbol := newSymbol(file.Path(), "", lsp.SymbolKindFile, file.Range)
+			symbol.SelectionRange = helper.LineRange(file.Range.Start.Line, 0, len(msg.Lines[file.Range.Start.Line]))
+			for _, hunk := range file.Hunks {
+				hunkSymbol := newSymbol(hunk.Header(), symbolName(hunk.Section), lsp.SymbolKindArray, hunk.Range)
+				hunkSymbol.SelectionRange = helper.LineRange(hunk.Range.Start.Line, 0, len(msg.Lines[hunk.Range.Start.Line]))
+				symbol.Children = append(symbol.Children, hunkSymbol)
+			}
+			diff.Children = append(diff.Children, symbol)
+		}
+		symbols = append(symbols, diff)
+	}
+
+	return symbols
+}
+
+func foldingRanges(msg *commit.Message) []lsp.FoldingRange {
+	ranges := []lsp.FoldingRange{}
+
+	for _, block := range commentBlocks(msg) {
+		if block.End.Line > block.Start.Line {
+			ranges = append(ranges, lsp.FoldingRange{
+				StartLine: block.Start.Line,
+				EndLine:   block.End.Line,
+				Kind:      lsp.FoldingRangeKindComment,
+			})
+		}
+	}
+
+	if msg.Scissors != -1 {
+		diff := diffSectionRange(msg)
+		if diff.End.Line > diff.Start.Line {
+			ranges = append(ranges, lsp.FoldingRange{
+				StartLine: diff.Start.Line,
+				EndLine:   diff.End.Line,
+				Kind:      lsp.FoldingRangeKindRegion,
+			})
+		}
+
+		for _, file := range msg.Diff {
+			if file.Range.End.Line > file.Range.Start.Line {
+				ranges = append(ranges, lsp.FoldingRange{
+					StartLine: file.Range.Start.Line,
+					EndLine:   file.Range.End.Line,
+					Kind:      lsp.FoldingRangeKindRegion,
+				})
+			}
+		}
+	}
+
+	return ranges
+}
+
+func (self *State) DocumentSymbol(id int, uri string) lsp.DocumentSymbolResponse {
+	symbols := []lsp.DocumentSymbol{}
+	if !self.isRebaseTodo(uri) {
+		symbols = documentSymbols(commit.ParseMessage(self.document(uri).Text))
+	}
+
+	return lsp.DocumentSymbolResponse{
+		Response: lsp.Response{
+			RPC: "2.0",
+			ID:  &id,
+		},
+		Result: symbols,
+	}
+}
+
+func (self *State) FoldingRange(id int, uri string) lsp.FoldingRangeResponse {
+	ranges := []lsp.FoldingRange{}
+	if !self.isRebaseTodo(uri) {
+		ranges = foldingRanges(commit.ParseMessage(self.document(uri).Text))
+	}
+
+	return lsp.FoldingRangeResponse{
+		Response: lsp.Response{
+			RPC: "2.0",
+			ID:  &id,
+		},
+		Result: ranges,
+	}
+}
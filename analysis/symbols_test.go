@@ -0,0 +1,96 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const verboseMessage = "feat(api): add endpoint\n" +
+	"\n" +
+	"Body paragraph\n" +
+	"\n" +
+	"Refs: #1\n" +
+	"\n" +
+	"# Please enter the commit message for your changes.\n" +
+	"# Lines starting with '#' will be ignored.\n" +
+	commit.ScissorsLine + "\n" +
+	"# Do not modify or remove the line above.\n" +
+	"diff --git a/api.go b/api.go\n" +
+	"index 1234567..89abcde 100644\n" +
+	"--- a/api.go\n" +
+	"+++ b/api.go\n" +
+	"@@ -1 +1,2 @@\n" +
+	" package api\n" +
+	"+func Endpoint() {}\n" +
+	"diff --git a/old.go b/old.go\n" +
+	"deleted file mode 100644\n" +
+	"--- a/old.go\n" +
+	"+++ /dev/null\n" +
+	"@@ -1 +0,0 @@\n" +
+	"-package api\n"
+
+func TestDocumentSymbols(t *testing.T) {
+	symbols := documentSymbols(commit.ParseMessage(verboseMessage))
+	require.Len(t, symbols, 5)
+
+	assert.Equal(t, "Header", symbols[0].Name)
+	require.Len(t, symbols[0].Children, 3)
+	assert.Equal(t, "feat", symbols[0].Children[0].Name)
+	assert.Equal(t, "api", symbols[0].Children[1].Name)
+	assert.Equal(t, "add endpoint", symbols[0].Children[2].Name)
+
+	assert.Equal(t, "Body", symbols[1].Name)
+	require.Len(t, symbols[1].Children, 1)
+	assert.Equal(t, "Body paragraph", symbols[1].Children[0].Name)
+
+	assert.Equal(t, "Footers", symbols[2].Name)
+	require.Len(t, symbols[2].Children, 1)
+	assert.Equal(t, "Refs", symbols[2].Children[0].Name)
+	assert.Equal(t, "#1", symbols[2].Children[0].Detail)
+	assert.Equal(t, helper.LineRange(4, 0, 4), symbols[2].Children[0].SelectionRange)
+
+	assert.Equal(t, "Comments", symbols[3].Name)
+	assert.Equal(t, lsp.Range{
+		Start: lsp.Position{Line: 6, Character: 0},
+		End:   lsp.Position{Line: 7, Character: 42},
+	}, symbols[3].Range)
+
+	assert.Equal(t, "Diff", symbols[4].Name)
+	require.Len(t, symbols[4].Children, 2)
+	assert.Equal(t, "api.go", symbols[4].Children[0].Name)
+	assert.Equal(t, lsp.Range{
+		Start: lsp.Position{Line: 10, Character: 0},
+		End:   lsp.Position{Line: 16, Character: 19},
+	}, symbols[4].Children[0].Range)
+	require.Len(t, symbols[4].Children[0].Children, 1)
+	assert.Equal(t, "@@ -1 +1,2 @@", symbols[4].Children[0].Children[0].Name)
+	assert.Equal(t, helper.LineRange(14, 0, 13), symbols[4].Children[0].Children[0].SelectionRange)
+	assert.Equal(t, "old.go", symbols[4].Children[1].Name)
+}
+
+func TestFoldingRanges(t *testing.T) {
+	assert.Equal(t, []lsp.FoldingRange{
+		{StartLine: 6, EndLine: 7, Kind: lsp.FoldingRangeKindComment},
+		{StartLine: 8, EndLine: 23, Kind: lsp.FoldingRangeKindRegion},
+		{StartLine: 10, EndLine: 16, Kind: lsp.FoldingRangeKindRegion},
+		{StartLine: 17, EndLine: 22, Kind: lsp.FoldingRangeKindRegion},
+	}, foldingRanges(commit.ParseMessage(verboseMessage)))
+}
+
+func TestSymbolName(t *testing.T) {
+	assert.Equal(t, "feat: add x", symbolName("feat: add x"))
+
+	long := strings.Repeat("é", maxSymbolNameLength+1)
+	name := symbolName(long)
+	assert.True(t, utf8.ValidString(name))
+	assert.Equal(t, maxSymbolNameLength, utf8.RuneCountInString(name))
+	assert.Equal(t, strings.Repeat("é", maxSymbolNameLength-3)+"...", name)
+	assert.Equal(t, strings.Repeat("é", maxSymbolNameLength), symbolName(strings.Repeat("é", maxSymbolNameLength)))
+}
@@ -0,0 +1,155 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/git"
+	"github.com/eamonburns/git-lsp/git/object"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// URI scheme of the read-only documents served by the server:
+//
+//	git-lsp://show/<rev>?root=<root>            `git show <rev>`
+//	git-lsp://message/<rev>?root=<root>         The message of a commit
+//	git-lsp://object/<rev>?root=<root>          The content of any object (e.g. "HEAD:README.md")
+//	git-lsp://blame/<path>?root=<root>&rev=<rev> `git blame` of a file, rev is optional
+const VirtualScheme = "git-lsp"
+
+// Kinds of virtual documents
+const (
+	virtualShow    = "show"
+	virtualMessage = "message"
+	virtualObject  = "object"
+	virtualBlame   = "blame"
+)
+
+type virtualDocument struct {
+	kind string
+	root string
+	// Revision, or the path of the file for blame
+	name string
+	// Revision of a blame
+	rev string
+}
+
+func (self virtualDocument) URI() string {
+	query := url.Values{"root": {self.root}}
+	if self.rev != "" {
+		query.Set("rev", self.rev)
+	}
+
+	return (&url.URL{
+		Scheme:   VirtualScheme,
+		Host:     self.kind,
+		Path:     "/" + self.name,
+		RawQuery: query.Encode(),
+	}).String()
+}
+
+func parseVirtualURI(uri string) (virtualDocument, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return virtualDocument{}, err
+	}
+	if parsed.Scheme != VirtualScheme {
+		return virtualDocument{}, fmt.Errorf("not a %s URI: %s", VirtualScheme, uri)
+	}
+
+	document := virtualDocument{
+		kind: parsed.Host,
+		root: parsed.Query().Get("root"),
+		name: strings.TrimPrefix(parsed.Path, "/"),
+		rev:  parsed.Query().Get("rev"),
+	}
+	if document.root == "" || document.name == "" {
+		return virtualDocument{}, fmt.Errorf("incomplete URI: %s", uri)
+	}
+
+	return document, nil
+}
+
+// The object reader of a repository, started on first use
+func (self *State) objectReader(root string) (*object.Reader, error) {
+	if reader, ok := self.readers[root]; ok {
+		return reader, nil
+	}
+
+	reader, err := object.NewReader(root)
+	if err != nil {
+		return nil, err
+	}
+	self.readers[root] = reader
+
+	return reader, nil
+}
+
+func (self *State) readObject(root string, read func(*object.Reader) (string, error)) (string, error) {
+	reader, err := self.objectReader(root)
+	if err != nil {
+		return "", err
+	}
+
+	text, err := read(reader)
+	if err != nil && !errors.Is(err, object.ErrNotFound) {
+		// The git process may have exited, so start a new one next time
+		reader.Close()
+		delete(self.readers, root)
+	}
+
+	return text, err
+}
+
+func (self *State) virtualContent(document virtualDocument) (string, error) {
+	switch document.kind {
+	case virtualShow:
+		return git.Show(document.root, document.name)
+	case virtualBlame:
+		return git.Blame(document.root, document.rev, document.name)
+	case virtualMessage:
+		return self.readObject(document.root, func(reader *object.Reader) (string, error) {
+			commit, err := reader.ReadCommit(document.name)
+			if err != nil {
+				return "", err
+			}
+			return commit.Message, nil
+		})
+	case virtualObject:
+		return self.readObject(document.root, func(reader *object.Reader) (string, error) {
+			content, err := reader.Read(document.name)
+			if err != nil {
+				return "", err
+			}
+			return string(content.Data), nil
+		})
+	default:
+		return "", fmt.Errorf("unknown document kind: %s", document.kind)
+	}
+}
+
+func (self *State) TextDocumentContent(id int, uri string) lsp.TextDocumentContentResponse {
+	response := lsp.TextDocumentContentResponse{
+		Response: lsp.Response{
+			RPC: "2.0",
+			ID:  &id,
+		},
+	}
+
+	document, err := parseVirtualURI(uri)
+	if err != nil {
+		response.Error = &lsp.ResponseError{Code: lsp.ErrorCodeInvalidParams, Message: err.Error()}
+		return response
+	}
+
+	text, err := self.virtualContent(document)
+	if err != nil {
+		response.Error = &lsp.ResponseError{Code: lsp.ErrorCodeRequestFailed, Message: err.Error()}
+		return response
+	}
+
+	response.Result = &lsp.TextDocumentContentResult{Text: text}
+	return response
+}
@@ -0,0 +1,58 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVirtualURI(t *testing.T) {
+	document := virtualDocument{kind: virtualBlame, root: "/home/user/my repo", name: "lua/min_init.lua", rev: "HEAD~1"}
+	uri := document.URI()
+	assert.Equal(t, "git-lsp://blame/lua/min_init.lua?rev=HEAD~1&root=%2Fhome%2Fuser%2Fmy+repo", uri)
+
+	parsed, err := parseVirtualURI(uri)
+	require.NoError(t, err)
+	assert.Equal(t, document, parsed)
+
+	_, err = parseVirtualURI("file:///tmp/x")
+	assert.Error(t, err)
+	_, err = parseVirtualURI("git-lsp://show/HEAD")
+	assert.Error(t, err)
+}
+
+func TestTextDocumentContent(t *testing.T) {
+	root, hash := newRepo(t)
+	state := NewState(t.TempDir())
+
+	content := func(kind string, name string) lsp.TextDocumentContentResponse {
+		return state.TextDocumentContent(1, virtualDocument{kind: kind, root: root, name: name}.URI())
+	}
+
+	response := content(virtualMessage, hash[:7])
+	require.Nil(t, response.Error)
+	assert.Equal(t, "feat: initial\n", response.Result.Text)
+
+	response = content(virtualObject, "HEAD:api/api.go")
+	require.Nil(t, response.Error)
+	assert.Equal(t, "package api\n", response.Result.Text)
+
+	response = content(virtualShow, "HEAD")
+	require.Nil(t, response.Error)
+	assert.Contains(t, response.Result.Text, "+package api")
+
+	response = content(virtualBlame, "api/api.go")
+	require.Nil(t, response.Error)
+	assert.Contains(t, response.Result.Text, "package api")
+
+	response = content(virtualMessage, "0000000")
+	require.NotNil(t, response.Error)
+	assert.Equal(t, lsp.ErrorCodeRequestFailed, response.Error.Code)
+	assert.Nil(t, response.Result)
+
+	response = state.TextDocumentContent(1, "git-lsp://show/HEAD")
+	require.NotNil(t, response.Error)
+	assert.Equal(t, lsp.ErrorCodeInvalidParams, response.Error.Code)
+}
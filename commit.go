@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/compose"
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/git"
+	"github.com/eamonburns/git-lsp/internal/helper"
+)
+
+func commitUsage() {
+	fmt.Fprintf(os.Stderr, "usage: git-lsp commit [git commit flags]\n\n")
+	fmt.Fprintf(os.Stderr, "Write a commit message by answering questions about its type, scope, description, body,\n")
+	fmt.Fprintf(os.Stderr, "breaking change and issue references, and commit it with `git commit -F -`. Each part is checked\n")
+	fmt.Fprintf(os.Stderr, "as soon as it is entered. The flags (e.g. --all or --amend) are passed to git commit\n")
+}
+
+// Compose a commit message on the terminal and commit the staged changes with it
+//
+// Returns the exit code
+func composeCommit(args []string) int {
+	if slices.Contains(args, "-h") || slices.Contains(args, "--help") {
+		commitUsage()
+		return 2
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	// RepoRoot expects a file, so look for the repository of a file in the directory
+	root := helper.RepoRoot(filepath.Join(dir, config.FileName))
+	if root == "" {
+		fmt.Fprintf(os.Stderr, "error: %s is not in a git repository\n", dir)
+		return 1
+	}
+
+	cfg, err := config.Load(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	staged, err := git.StagedFiles(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	if len(staged) == 0 && len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "error: no changes added to commit (use \"git add\", or pass --all)")
+		return 1
+	}
+
+	composer := compose.New(os.Stdin, os.Stdout, commit.Repo{Root: root}, cfg, staged)
+	message, err := composer.Compose()
+	if errors.Is(err, compose.ErrAborted) {
+		fmt.Fprintln(os.Stderr, "\nAborted")
+		return 1
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+
+	cmd := exec.Command("git", append([]string{"-C", root, "commit", "-F", "-"}, args...)...)
+	cmd.Stdin = strings.NewReader(message)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode()
+		}
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
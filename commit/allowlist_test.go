@@ -0,0 +1,132 @@
+package commit
+
+import (
+	"testing"
+
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/internal/testrepo"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("feat", "feat"))
+	assert.Equal(t, 1, editDistance("feta", "feat"))
+	assert.Equal(t, 1, editDistance("fx", "fix"))
+	assert.Equal(t, 3, editDistance("", "fix"))
+	assert.Equal(t, 4, editDistance("docs", "feat"))
+}
+
+func TestSuggestions(t *testing.T) {
+	names := []string{"feat", "fix", "docs", "perf", "refactor"}
+	assert.Equal(t, []string{"feat"}, Suggestions("feta", names))
+	assert.Equal(t, []string{"refactor"}, Suggestions("refactr", names))
+	assert.Equal(t, []string{"feat"}, Suggestions("FEAF", names))
+	// Ties are in the order of the candidates
+	assert.Equal(t, []string{"fit", "feat"}, Suggestions("fet", []string{"fix", "fit", "feat"}))
+	assert.Empty(t, Suggestions("chore", names))
+}
+
+func TestTypeAllowlist(t *testing.T) {
+	check := func(text string) []Diagnostic {
+		return Check(&Context{Message: ParseMessage(text)})
+	}
+
+	assert.Empty(t, check("feat: x"))
+	assert.Empty(t, check("Merge branch 'x'"))
+
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(0, 0, 4),
+		Type:  TypeCaseWarning,
+		Args:  []string{"Feat", "feat"},
+		Fixes: []Fix{{Title: "Change to 'feat'", Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 0, 4), NewText: "feat"}}}},
+	}}, check("Feat: x"))
+
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(0, 0, 4),
+		Type:  UnknownTypeWarning,
+		Args:  []string{"feta"},
+		Fixes: []Fix{{Title: "Change to 'feat'", Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 0, 4), NewText: "feat"}}}},
+	}}, check("feta: x"))
+
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(0, 0, 5),
+		Type:  UnknownTypeWarning,
+		Args:  []string{"style"},
+		Fixes: []Fix{},
+	}}, Check(&Context{Message: ParseMessage("style: x"), Config: &config.Config{Types: []config.CommitType{{Name: "feat"}}}}))
+}
+
+func TestScopeAllowlist(t *testing.T) {
+	root := testrepo.New(t, "feat(parser): a", "fix(old): b", "fix(lexer, docs/old): c")
+
+	cfg := config.Default()
+	check := func(text string) []Diagnostic {
+		return Check(&Context{Message: ParseMessage(text), Repo: Repo{Root: root}, Config: cfg})
+	}
+
+	// Any scope is allowed by default
+	assert.Empty(t, check("feat(anything): x"))
+
+	assert.Empty(t, check("feat(api, ui): x"))
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(0, 6, 7),
+		Type:  ScopeWhitespaceWarning,
+		Fixes: []Fix{{Title: "Remove whitespace", Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 6, 7), NewText: ""}}}},
+	}}, check("fix(ui ): x"))
+	assert.Equal(t, []Diagnostic{
+		{
+			Range: helper.LineRange(0, 4, 5),
+			Type:  ScopeWhitespaceWarning,
+			Fixes: []Fix{{Title: "Remove whitespace", Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 4, 5), NewText: ""}}}},
+		},
+		{
+			Range: helper.LineRange(0, 9, 15),
+			Type:  ScopeWhitespaceWarning,
+			Fixes: []Fix{{Title: "Change to 'ui-kit'", Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 9, 15), NewText: "ui-kit"}}}},
+		},
+	}, check("fix( api,ui kit): x"))
+
+	cfg.Scopes = []string{"api"}
+	cfg.ScopesFromHistory = true
+	cfg.DeprecatedScopes = []string{"old"}
+	assert.Empty(t, check("feat(api): x"))
+	assert.Empty(t, check("feat(parser): x"))
+	assert.Empty(t, check("feat(lexer): x"))
+	assert.Empty(t, check("feat(docs): x"))
+	assert.Equal(t, []string{"lexer", "docs", "parser"}, HistoryScopes(Repo{Root: root}, cfg))
+
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(0, 5, 8),
+		Type:  ScopeCaseWarning,
+		Args:  []string{"API", "api"},
+		Fixes: []Fix{{Title: "Change to 'api'", Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 5, 8), NewText: "api"}}}},
+	}}, check("feat(API): x"))
+
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(0, 5, 11),
+		Type:  UnknownScopeWarning,
+		Args:  []string{"parsre"},
+		Fixes: []Fix{{Title: "Change to 'parser'", Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 5, 11), NewText: "parser"}}}},
+	}}, check("feat(parsre): x"))
+
+	// Each scope is checked
+	assert.Empty(t, check("feat(api/parser): x"))
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(0, 10, 13),
+		Type:  UnknownScopeWarning,
+		Args:  []string{"apj"},
+		Fixes: []Fix{{Title: "Change to 'api'", Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 10, 13), NewText: "api"}}}},
+	}}, check("feat(ui,  apj): x")[1:])
+
+	cfg.ScopeDelimiters = ","
+	diagnostics := check("feat(api/parser): x")
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, []string{"api/parser"}, diagnostics[0].Args)
+
+	diagnostics = check("feat(old): x")
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, UnknownScopeWarning, diagnostics[0].Type)
+}
@@ -0,0 +1,75 @@
+package commit
+
+import (
+	"testing"
+
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreakingChange(t *testing.T) {
+	cfg := config.Default()
+	check := func(text string, rule DiagnosticType) []Diagnostic {
+		diagnostics := []Diagnostic{}
+		for _, diagnostic := range Check(&Context{Message: ParseMessage(text), Config: cfg}) {
+			if diagnostic.Type == rule {
+				diagnostics = append(diagnostics, diagnostic)
+			}
+		}
+		return diagnostics
+	}
+
+	assert.Empty(t, check("feat!: drop x", EmptyBreakingChangeError))
+	assert.Empty(t, check("feat!: drop x\n\nBREAKING CHANGE: x is gone", MissingBreakingBangWarning))
+	assert.Empty(t, check("feat!: drop x", MissingBreakingFooterWarning))
+
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(2, 0, 17),
+		Type:  EmptyBreakingChangeError,
+	}}, check("feat!: drop x\n\nBREAKING CHANGE: ", EmptyBreakingChangeError))
+
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(0, 4, 5),
+		Type:  EmptyBreakingChangeError,
+		Fixes: []Fix{{
+			Title: "Add BREAKING CHANGE footer",
+			Edits: []lsp.TextEdit{{Range: helper.LineRange(2, 9, 9), NewText: "\nBREAKING CHANGE: "}},
+		}},
+	}}, check("feat!:\n\nRefs: #12", EmptyBreakingChangeError))
+
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(2, 0, 15),
+		Type:  MissingBreakingBangWarning,
+		Fixes: []Fix{{
+			Title: "Add '!' to the header",
+			Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 9, 9), NewText: "!"}},
+		}},
+	}}, check("feat(api): drop x\n\nBREAKING CHANGE: x is gone", MissingBreakingBangWarning))
+
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(2, 0, 18),
+		Type:  MisspelledBreakingFooterWarning,
+		Args:  []string{"breaking changes: "},
+		Fixes: []Fix{{
+			Title: "Change to 'BREAKING CHANGE: '",
+			Edits: []lsp.TextEdit{{Range: helper.LineRange(2, 0, 18), NewText: "BREAKING CHANGE: "}},
+		}},
+	}}, check("feat!: drop x\n\nbreaking changes: x is gone", MisspelledBreakingFooterWarning))
+	assert.Empty(t, check("feat!: drop x\n\nBREAKING-CHANGE: x is gone", MisspelledBreakingFooterWarning))
+	assert.Len(t, check("feat!: drop x\n\nBreaking-Change #x", MisspelledBreakingFooterWarning), 1)
+
+	cfg.BreakingChange = config.BreakingChangeAny
+	assert.Empty(t, check("feat: drop x\n\nBREAKING CHANGE: x is gone", MissingBreakingBangWarning))
+
+	cfg.BreakingChange = config.BreakingChangeBoth
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(0, 4, 5),
+		Type:  MissingBreakingFooterWarning,
+		Fixes: []Fix{{
+			Title: "Add BREAKING CHANGE footer",
+			Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 13, 13), NewText: "\n\nBREAKING CHANGE: drop x"}},
+		}},
+	}}, check("feat!: drop x", MissingBreakingFooterWarning))
+}
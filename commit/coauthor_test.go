@@ -0,0 +1,54 @@
+package commit
+
+import (
+	"testing"
+
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/internal/testrepo"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoAuthors(t *testing.T) {
+	root := testrepo.Init(t)
+	testrepo.CommitAs(t, root, "Ann", "ann@example.com", "feat: a")
+	testrepo.CommitAs(t, root, "Bob", "123+bob@users.noreply.github.com", "feat: b")
+
+	check := func(text string) []Diagnostic {
+		return Check(&Context{Message: ParseMessage(text), Repo: Repo{Root: root}})
+	}
+
+	assert.Empty(t, check("feat: x\n\nCo-authored-by: Ann <ANN@example.com>"))
+	// Not checked outside of a repository
+	assert.Empty(t, Check(&Context{Message: ParseMessage("feat: x\n\nCo-authored-by: Eve <eve@example.com>")}))
+
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(2, 16, 37),
+		Type:  UnknownCoAuthorWarning,
+		Args:  []string{"eve@example.com"},
+	}}, check("feat: x\n\nCo-authored-by: Eve <eve@example.com>"))
+
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(2, 16, 37),
+		Type:  UnknownCoAuthorWarning,
+		Args:  []string{"ann@example.org"},
+		Fixes: []Fix{{
+			Title: "Replace with Ann <ann@example.com>",
+			Edits: []lsp.TextEdit{{Range: helper.LineRange(2, 16, 37), NewText: "Ann <ann@example.com>"}},
+		}},
+	}}, check("feat: x\n\nCo-authored-by: Ann <ann@example.org>"))
+
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(2, 16, 48),
+		Type:  NoreplyMismatchWarning,
+		Args:  []string{"bob@users.noreply.github.com", "123+bob@users.noreply.github.com"},
+		Fixes: []Fix{{
+			Title: "Replace with Bob <123+bob@users.noreply.github.com>",
+			Edits: []lsp.TextEdit{{Range: helper.LineRange(2, 16, 48), NewText: "Bob <123+bob@users.noreply.github.com>"}},
+		}},
+	}}, check("feat: x\n\nCo-authored-by: B <bob@users.noreply.github.com>"))
+
+	// The authors are read again once HEAD changes
+	testrepo.CommitAs(t, root, "Eve", "eve@example.com", "feat: c")
+	assert.Empty(t, check("feat: x\n\nCo-authored-by: Eve <eve@example.com>"))
+}
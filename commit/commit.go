@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/eamonburns/git-lsp/internal/helper"
 	"github.com/eamonburns/git-lsp/lsp"
 )
 
@@ -13,182 +12,125 @@ import (
 // Conventional Commits Specification: https://www.conventionalcommits.org/en/v1.0.0/#specification
 
 type Commit struct {
+	// Whether the message was generated by git (e.g. a merge), instead of following the
+	// Conventional Commits format
+	Kind Kind `json:"kind,omitempty"`
+
+	// The subject of the commit that is reverted, fixed up, squashed or amended
+	Target string `json:"target,omitempty"`
+
 	// e.g. feat, fix, docs
-	Type string
+	Type string `json:"type"`
+
+	Scope string `json:"scope"`
 
-	Scope string
+	// The scope split at the scope delimiters (e.g. ["api", "ui"] for "api,ui"), without
+	// whitespace around each scope
+	Scopes []string `json:"scopes,omitempty"`
 
 	// Description of the breaking change (if any)
 	// If the breaking change is specified by a "!" in the type/scope
 	// prefix, and there is no BREAKING CHANGE footer, then this will
 	// be the same as Description
-	BreakingChange string
+	BreakingChange string `json:"breakingChange"`
 
-	Description string
+	Description string `json:"description"`
 
-	Body string
+	Body string `json:"body"`
 
-	Footers map[string]string
+	Footers map[string]string `json:"footers"`
 }
 
+// Parse a commit message and check it with all registered rules
+//
+// Use ParseMessage and Check directly to get the position of each part of the message, or to
+// provide information about the repository to the rules
 func Parse(text string) (Commit, []Diagnostic) {
-	diagnostics := []Diagnostic{}
-	header, rest, _ := strings.Cut(text, "\n")
-
-	commit := Commit{}
-
-	typeScope, description, foundTypeScope := strings.Cut(header, ":")
-	if foundTypeScope {
-		if strings.TrimSpace(description) == "" {
-			diagnostics = append(diagnostics, Diagnostic{
-				Range: helper.LineRange(0, len(typeScope)+1, len(header)),
-				Type:  EmptyDescriptionError,
-			})
-		} else if description[0] != ' ' {
-			diagnostics = append(diagnostics, Diagnostic{
-				Range: helper.LineRange(0, len(typeScope)+1, len(typeScope)+1),
-				Type:  NoSpaceBeforeDescriptionError,
-			})
-		}
-
-		description = strings.TrimSpace(description)
-		commit.Description = description
+	msg := ParseMessage(text)
+	diagnostics := Suppress(msg, Check(&Context{Message: msg}), nil)
 
-		// Check for breaking change "!"
-		if idx := strings.LastIndex(typeScope, "!"); idx != -1 {
-			// Remove "!"
-			typeScope = typeScope[:idx] // Check to make sure
-
-			// "13. If included in the type/scope prefix, breaking changes MUST be indicated by a ! immediately before the :. If ! is used, BREAKING CHANGE: MAY be omitted from the footer section, and the commit description SHALL be used to describe the breaking change."
-			// NOTE: I am interpreting the above requirement to mean that if the BREAKING CHANGE footer is included, that is used instead of the description
-			commit.BreakingChange = description
-		}
-
-		// Extract scope if present
-		if lParIdx := strings.Index(typeScope, "("); lParIdx != -1 {
-			commit.Type = typeScope[:lParIdx]
-
-			rParIdx := strings.Index(typeScope, ")")
-
-			if rParIdx == -1 {
-				commit.Scope = typeScope[lParIdx+1:]
-
-				diagnostics = append(diagnostics, Diagnostic{
-					Range: helper.LineRange(0, lParIdx, lParIdx),
-					Type:  UnmatchedLeftParenError,
-				})
-			} else if rParIdx < len(typeScope)-1 {
-				// The right parentheses is not the last character of typeScope
-
-				commit.Scope = typeScope[lParIdx+1 : rParIdx]
-
-				diagnostics = append(diagnostics, Diagnostic{
-					Range: helper.LineRange(0, rParIdx+1, len(typeScope)),
-					Type:  ExtraCharactersAfterScopeError,
-					Args:  []string{typeScope[rParIdx+1:]},
-				})
-			} else {
-				commit.Scope = typeScope[lParIdx+1 : rParIdx]
-			}
-		} else if idx := strings.Index(typeScope, ")"); idx != -1 {
-			// There wasn't a '(', but there was a ')'
-
-			diagnostics = append(diagnostics, Diagnostic{
-				Range: helper.LineRange(0, idx, idx),
-				Type:  UnmatchedRightParenError,
-			})
-			commit.Type = typeScope[:idx]
-		} else {
-			commit.Type = typeScope
-		}
-	} else {
-		diagnostics = append(diagnostics, Diagnostic{
-			Range: helper.LineRange(0, 0, len(header)),
-			Type:  NoTypeScopeError,
-		})
-		commit.Description = typeScope // Header line wasn't split, so typeScope is the whole line, which we will use as the description
-	}
-
-	// TODO: Parse body/footers
-	// NOTE: Ignore all lines starting with #
-	_ = rest
-
-	if foundTypeScope && commit.Type == "" {
-		diagnostics = append(diagnostics, Diagnostic{
-			Range: helper.LineRange(0, 0, 0),
-			Type:  EmptyTypeError,
-		})
-	}
-	if idx := strings.Index(typeScope, "("); idx != -1 && foundTypeScope && commit.Scope == "" {
-		diagnostics = append(diagnostics, Diagnostic{
-			Range: helper.LineRange(0, len(commit.Type), len(commit.Type)+2),
-			Type:  EmptyScopeError,
-		})
-	}
-
-	return commit, diagnostics
+	return msg.Commit, diagnostics
 }
 
-type DiagnosticType int
+// The stable ID of the rule that produced a diagnostic (e.g. "header/empty-scope")
+//
+// IDs are used to refer to rules in configuration and suppression comments, so they must never change
+type DiagnosticType string
 
 // Diagnostic error/warning types
 const (
 	// There was no type/scope in the header line (e.g. "description")
-	NoTypeScopeError DiagnosticType = iota
+	NoTypeScopeError DiagnosticType = "header/no-type-scope"
 	// There was a left parentheses in the type/scope, but no matching right parentheses (e.g. "type(scope: description")
-	UnmatchedLeftParenError
+	UnmatchedLeftParenError DiagnosticType = "header/unmatched-left-paren"
 	// There was a right parentheses in the type/scope, but no matching left parentheses (e.g. "typescope): description")
-	UnmatchedRightParenError
+	UnmatchedRightParenError DiagnosticType = "header/unmatched-right-paren"
 	// There were extra characters after the scope (e.g. "type(scope)bla: description")
 	// Args: 0 = characters
-	ExtraCharactersAfterScopeError
+	ExtraCharactersAfterScopeError DiagnosticType = "header/extra-characters-after-scope"
 	// The type in the type/scope was empty (e.g. "(scope): description")
-	EmptyTypeError
+	EmptyTypeError DiagnosticType = "header/empty-type"
 	// The scope in the type/scope was empty (e.g. "type(): description")
-	EmptyScopeError
+	EmptyScopeError DiagnosticType = "header/empty-scope"
 	// The description was empty (e.g. "type(scope):", "type(scope):    ")
-	EmptyDescriptionError
+	EmptyDescriptionError DiagnosticType = "header/empty-description"
 	// There was no space between the colon and description (e.g. "type(scope):description")
-	NoSpaceBeforeDescriptionError
+	NoSpaceBeforeDescriptionError DiagnosticType = "header/no-space-before-description"
 )
 
 type Diagnostic struct {
 	Range lsp.Range
 	Type  DiagnosticType
 	Args  []string
+
+	// Message to show, instead of the message of the rule
+	// Rules that are not built in to git-lsp must set this
+	Message string
+
+	// Severity to use, instead of the default severity of the rule (if not 0)
+	Severity lsp.DiagnosticSeverity
+
+	// Ways to fix the problem (if any)
+	Fixes []Fix
 }
 
-func (self Diagnostic) ToLspDiagnostic() lsp.Diagnostic {
-	var message string
-	var severity int = 1
-
-	switch self.Type {
-	case NoTypeScopeError:
-		message = "No type/scope in header line"
-	case UnmatchedLeftParenError:
-		message = "Unmatched '('"
-	case UnmatchedRightParenError:
-		message = "Unmatched ')'"
-	case ExtraCharactersAfterScopeError:
-		message = fmt.Sprintf("Extra characters after scope: '%s'", self.Args[0])
-	case EmptyTypeError:
-		message = "Empty type"
-	case EmptyScopeError:
-		message = "Empty scope"
-	case EmptyDescriptionError:
-		message = "Empty description"
-	case NoSpaceBeforeDescriptionError:
-		message = "No space before description"
-	default:
-		message = "Unknown error"
-	}
+// A change to the commit message that fixes the problem reported by a diagnostic
+type Fix struct {
+	Title string
+	Edits []lsp.TextEdit
+}
 
-	return lsp.Diagnostic{
+func (self Diagnostic) ToLspDiagnostic() lsp.Diagnostic {
+	diagnostic := lsp.Diagnostic{
 		Range:    self.Range,
-		Severity: severity,
+		Severity: lsp.DiagnosticSeverityError,
 		Source:   "git-lsp",
-		Message:  message,
+		Message:  self.Message,
+		Code:     string(self.Type),
+	}
+
+	rule, ok := LookupRule(self.Type)
+	if diagnostic.Message == "" && ok && rule.Message != nil {
+		diagnostic.Message = rule.Message(self)
 	}
+	if diagnostic.Message == "" && ok {
+		diagnostic.Message = rule.Description
+	}
+	if diagnostic.Message == "" {
+		diagnostic.Message = "Unknown error"
+	}
+
+	if ok {
+		diagnostic.Severity = rule.Severity
+		if rule.DocURL != "" {
+			diagnostic.CodeDescription = &lsp.CodeDescription{Href: rule.DocURL}
+		}
+	}
+	if self.Severity != 0 {
+		diagnostic.Severity = self.Severity
+	}
+
+	return diagnostic
 }
 
 func ParseFooter(s string) (string, string, bool) {
@@ -199,8 +141,6 @@ func ParseFooter(s string) (string, string, bool) {
 	colonSpaceIdx := strings.Index(s, ": ")
 	spaceHashIdx := strings.Index(s, " #")
 
-	fmt.Printf("string: '%s', colon: %d, hash: %d\n", s, colonSpaceIdx, spaceHashIdx)
-
 	if colonSpaceIdx == -1 && spaceHashIdx == -1 {
 		// Neither were found
 		return "", "", false
@@ -3,25 +3,28 @@ package commit
 import (
 	"testing"
 
+	"github.com/eamonburns/git-lsp/config"
 	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func TestParse(t *testing.T) {
-	commit, diagnostics := Parse("type(scope)!: description")
+	commit, diagnostics := Parse("feat(scope)!: description")
 	require.Empty(t, diagnostics)
 	assert.Equal(t, Commit{
-		Type:           "type",
+		Type:           "feat",
 		Scope:          "scope",
+		Scopes:         []string{"scope"},
 		BreakingChange: "description",
 		Description:    "description",
 	}, commit)
 
-	commit, diagnostics = Parse("type: description")
+	commit, diagnostics = Parse("feat: description")
 	require.Empty(t, diagnostics)
 	assert.Equal(t, Commit{
-		Type:           "type",
+		Type:           "feat",
 		Scope:          "",
 		BreakingChange: "",
 		Description:    "description",
@@ -39,21 +42,26 @@ func TestParse(t *testing.T) {
 		Description: commitMsg,
 	}, commit)
 
-	commit, diagnostics = Parse("type(scope)bla: description")
+	commit, diagnostics = Parse("feat(scope)bla: description")
 	assert.ElementsMatch(t, []Diagnostic{
 		{
 			Range: helper.LineRange(0, 11, 14),
 			Type:  ExtraCharactersAfterScopeError,
 			Args:  []string{"bla"},
+			Fixes: []Fix{{
+				Title: "Remove extra characters",
+				Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 11, 14), NewText: ""}},
+			}},
 		},
 	}, diagnostics)
 	assert.Equal(t, Commit{
-		Type:        "type",
+		Type:        "feat",
 		Scope:       "scope",
+		Scopes:      []string{"scope"},
 		Description: "description",
 	}, commit)
 
-	commit, diagnostics = Parse("type(scope: description")
+	commit, diagnostics = Parse("feat(scope: description")
 	assert.ElementsMatch(t, []Diagnostic{
 		{
 			Range: helper.LineRange(0, 4, 4),
@@ -61,8 +69,9 @@ func TestParse(t *testing.T) {
 		},
 	}, diagnostics)
 	assert.Equal(t, Commit{
-		Type:        "type",
+		Type:        "feat",
 		Scope:       "scope",
+		Scopes:      []string{"scope"},
 		Description: "description",
 	}, commit)
 
@@ -89,18 +98,23 @@ func TestParse(t *testing.T) {
 	assert.Equal(t, Commit{
 		Type:        "",
 		Scope:       "scope",
+		Scopes:      []string{"scope"},
 		Description: "description",
 	}, commit)
 
-	commit, diagnostics = Parse("type(): description")
+	commit, diagnostics = Parse("feat(): description")
 	assert.ElementsMatch(t, []Diagnostic{
 		{
 			Range: helper.LineRange(0, 4, 6),
 			Type:  EmptyScopeError,
+			Fixes: []Fix{{
+				Title: "Remove empty scope",
+				Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 4, 6), NewText: ""}},
+			}},
 		},
 	}, diagnostics)
 	assert.Equal(t, Commit{
-		Type:        "type",
+		Type:        "feat",
 		Scope:       "",
 		Description: "description",
 	}, commit)
@@ -110,6 +124,10 @@ func TestParse(t *testing.T) {
 		{
 			Range: helper.LineRange(0, 0, 2),
 			Type:  EmptyScopeError,
+			Fixes: []Fix{{
+				Title: "Remove empty scope",
+				Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 0, 2), NewText: ""}},
+			}},
 		},
 		{
 			Range: helper.LineRange(0, 0, 0),
@@ -122,7 +140,7 @@ func TestParse(t *testing.T) {
 		Description: "description",
 	}, commit)
 
-	commit, diagnostics = Parse("type(scope):")
+	commit, diagnostics = Parse("feat(scope):")
 	assert.ElementsMatch(t, []Diagnostic{
 		{
 			Range: helper.LineRange(0, 12, 12),
@@ -130,21 +148,27 @@ func TestParse(t *testing.T) {
 		},
 	}, diagnostics)
 	assert.Equal(t, Commit{
-		Type:        "type",
+		Type:        "feat",
 		Scope:       "scope",
+		Scopes:      []string{"scope"},
 		Description: "",
 	}, commit)
 
-	commit, diagnostics = Parse("type(scope):description")
+	commit, diagnostics = Parse("feat(scope):description")
 	assert.ElementsMatch(t, []Diagnostic{
 		{
 			Range: helper.LineRange(0, 12, 12),
 			Type:  NoSpaceBeforeDescriptionError,
+			Fixes: []Fix{{
+				Title: "Insert space before description",
+				Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 12, 12), NewText: " "}},
+			}},
 		},
 	}, diagnostics)
 	assert.Equal(t, Commit{
-		Type:        "type",
+		Type:        "feat",
 		Scope:       "scope",
+		Scopes:      []string{"scope"},
 		Description: "description",
 	}, commit)
 }
@@ -188,3 +212,101 @@ func TestParseFooter(t *testing.T) {
 	footer, value, ok = ParseFooter("BREAKING CHANGE # : value")
 	require.False(t, ok)
 }
+
+func TestParseMessage(t *testing.T) {
+	msg := ParseMessage("feat(scope)!: description\n" +
+		"\n" +
+		"First paragraph\n" +
+		"of the body\n" +
+		"# A comment\n" +
+		"\n" +
+		"Second paragraph\n" +
+		"\n" +
+		"Refs: #123\n" +
+		"BREAKING CHANGE: it broke\n" +
+		"  very badly\n" +
+		"Closes #42\n" +
+		"\n" +
+		"# Please enter the commit message for your changes.\n" +
+		ScissorsLine + "\n" +
+		"diff --git a/file b/file\n")
+
+	assert.Equal(t, Commit{
+		Type:           "feat",
+		Scope:          "scope",
+		Scopes:         []string{"scope"},
+		BreakingChange: "it broke\nvery badly",
+		Description:    "description",
+		Body:           "First paragraph\nof the body\n\nSecond paragraph",
+		Footers: map[string]string{
+			"Refs":            "#123",
+			"BREAKING CHANGE": "it broke\nvery badly",
+			"Closes":          "42",
+		},
+	}, msg.Commit)
+
+	assert.Equal(t, helper.LineRange(0, 0, 4), msg.Header.TypeRange)
+	assert.Equal(t, helper.LineRange(0, 5, 10), msg.Header.ScopeRange)
+	assert.Equal(t, helper.LineRange(0, 14, 25), msg.Header.DescriptionRange)
+	assert.Equal(t, 11, msg.Header.Bang)
+	assert.Equal(t, 12, msg.Header.Colon)
+
+	assert.Equal(t, []lsp.Range{
+		{Start: lsp.Position{Line: 2, Character: 0}, End: lsp.Position{Line: 3, Character: 11}},
+		{Start: lsp.Position{Line: 6, Character: 0}, End: lsp.Position{Line: 6, Character: 16}},
+	}, msg.Body)
+
+	require.Len(t, msg.Footers, 3)
+	assert.Equal(t, helper.LineRange(8, 0, 4), msg.Footers[0].KeyRange)
+	assert.Equal(t, helper.LineRange(8, 6, 10), msg.Footers[0].ValueRange)
+	assert.Equal(t, lsp.Range{
+		Start: lsp.Position{Line: 9, Character: 0},
+		End:   lsp.Position{Line: 10, Character: 12},
+	}, msg.Footers[1].Range)
+	assert.Equal(t, " #", msg.Footers[2].Separator)
+	assert.Equal(t, helper.LineRange(11, 8, 10), msg.Footers[2].ValueRange)
+
+	assert.Equal(t, []int{4, 13}, msg.Comments)
+	assert.Equal(t, 14, msg.Scissors)
+	assert.True(t, msg.IsComment(15))
+	assert.False(t, msg.IsComment(2))
+}
+
+func TestSplitScopes(t *testing.T) {
+	msg := ParseMessage("feat(api, ui/auth,,): x")
+	assert.Equal(t, []string{"api", "ui", "auth"}, msg.Commit.Scopes)
+	assert.Equal(t, []lsp.Range{helper.LineRange(0, 5, 8), helper.LineRange(0, 10, 12), helper.LineRange(0, 13, 17)}, msg.Header.ScopeRanges)
+
+	msg.SplitScopes(",")
+	assert.Equal(t, []string{"api", "ui/auth"}, msg.Commit.Scopes)
+	assert.Equal(t, []lsp.Range{helper.LineRange(0, 5, 8), helper.LineRange(0, 10, 17)}, msg.Header.ScopeRanges)
+
+	assert.Nil(t, ParseMessage("feat: x").Commit.Scopes)
+
+	msg = ParseMessage("feat(api·ü): x")
+	msg.SplitScopes("·")
+	assert.Equal(t, []string{"api", "ü"}, msg.Commit.Scopes)
+	assert.Equal(t, []lsp.Range{helper.LineRange(0, 5, 8), helper.LineRange(0, 10, 12)}, msg.Header.ScopeRanges)
+}
+
+func TestMergeScopes(t *testing.T) {
+	_, diagnostics := Parse("feat(api)(ui)(db): x")
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, Fix{
+		Title: "Merge scopes",
+		Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 8, 17), NewText: ",ui,db)"}},
+	}, diagnostics[0].Fixes[1])
+
+	mergeScopes := func(cfg *config.Config) string {
+		for _, diagnostic := range Check(&Context{Message: ParseMessage("feat(api)(ui): x"), Config: cfg}) {
+			if diagnostic.Type == ExtraCharactersAfterScopeError {
+				return diagnostic.Fixes[1].Edits[0].NewText
+			}
+		}
+		return ""
+	}
+	// The first delimiter is a whole character, and configurations without delimiters use the
+	// default ones
+	assert.Equal(t, "·ui)", mergeScopes(&config.Config{ScopeDelimiters: "·,"}))
+	assert.Equal(t, ",ui)", mergeScopes(&config.Config{}))
+}
@@ -0,0 +1,107 @@
+package commit
+
+import (
+	"testing"
+
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImperativeOf(t *testing.T) {
+	for word, expected := range map[string]string{
+		"added":     "add",
+		"Adds":      "add",
+		"fixes":     "fix",
+		"applied":   "apply",
+		"updated":   "update",
+		"dropped":   "drop",
+		"wrapped":   "wrap",
+		"showed":    "show",
+		"shown":     "show",
+		"ran":       "run",
+		"runned":    "",
+		"splitted":  "",
+		"Formatted": "format",
+		"limited":   "limit",
+		"fixed":     "fix",
+		"tweaked":   "tweak",
+		"limitted":  "",
+		"wraped":    "",
+		"rewrote":   "rewrite",
+		"pushes":    "",
+		"add":       "",
+		"processes": "",
+	} {
+		imperative, ok := imperativeOf(word)
+		assert.Equal(t, expected != "", ok, word)
+		assert.Equal(t, expected, imperative, word)
+	}
+}
+
+func TestDescriptionStyle(t *testing.T) {
+	cfg := config.Default()
+	check := func(text string) []Diagnostic {
+		return Check(&Context{Message: ParseMessage(text), Config: cfg})
+	}
+
+	assert.Empty(t, check("feat: add x"))
+	assert.Empty(t, check("feat: API for x"))
+	assert.Empty(t, check("feat: GitHub login"))
+	assert.Empty(t, check("feat: wait for it..."))
+	assert.Empty(t, check("Merge branch 'Fixed.'"))
+
+	assert.Equal(t, []Diagnostic{
+		{
+			Range: helper.LineRange(0, 6, 11),
+			Type:  DescriptionCaseWarning,
+			Args:  []string{"lower"},
+			Fixes: []Fix{{Title: "Change to 'added'", Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 6, 11), NewText: "added"}}}},
+		},
+		{
+			Range: helper.LineRange(0, 13, 14),
+			Type:  DescriptionTrailingPeriodWarning,
+			Fixes: []Fix{{Title: "Remove trailing period", Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 13, 14), NewText: ""}}}},
+		},
+		{
+			Range: helper.LineRange(0, 6, 11),
+			Type:  DescriptionNotImperativeWarning,
+			Args:  []string{"Added", "Add"},
+			Fixes: []Fix{{Title: "Change to 'Add'", Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 6, 11), NewText: "Add"}}}},
+		},
+	}, check("feat: Added x."))
+
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(0, 5, 11),
+		Type:  DescriptionNotImperativeWarning,
+		Args:  []string{"showed", "show"},
+		Fixes: []Fix{{Title: "Change to 'show'", Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 5, 11), NewText: "show"}}}},
+	}}, check("fix: showed the error"))
+
+	assert.Equal(t, []Diagnostic{
+		{
+			Range: helper.LineRange(0, 5, 7),
+			Type:  DescriptionWhitespaceWarning,
+			Fixes: []Fix{{Title: "Replace with a single space", Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 5, 7), NewText: " "}}}},
+		},
+		{
+			Range: helper.LineRange(0, 12, 14),
+			Type:  DescriptionWhitespaceWarning,
+			Fixes: []Fix{{Title: "Remove trailing whitespace", Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 12, 14), NewText: ""}}}},
+		},
+	}, check("feat:  add x \t"))
+
+	cfg.Description.Case = config.CaseSentence
+	assert.Empty(t, check("feat: Add x"))
+	assert.Empty(t, check("feat: iOS support"))
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(0, 6, 9),
+		Type:  DescriptionCaseWarning,
+		Args:  []string{"sentence"},
+		Fixes: []Fix{{Title: "Change to 'Add'", Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 6, 9), NewText: "Add"}}}},
+	}}, check("feat: add x"))
+
+	cfg.Description.Case = config.CaseAny
+	assert.Empty(t, check("feat: Add x"))
+}
@@ -0,0 +1,263 @@
+package commit
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// A file in the diff below the scissors line (added by `git commit --verbose`)
+type DiffFile struct {
+	// Path of the file before and after the change ("/dev/null" if it was added or deleted)
+	OldPath string
+	NewPath string
+
+	// From the "diff --git" line to the end of the last hunk
+	Range lsp.Range
+
+	Hunks []DiffHunk
+}
+
+// A "@@ -1,2 +1,3 @@" section of a file diff
+type DiffHunk struct {
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	// Text after the second "@@" (usually the enclosing function)
+	Section string
+
+	// Number of added/removed lines
+	Additions int
+	Deletions int
+
+	// From the "@@" line to the last line of the hunk
+	Range lsp.Range
+}
+
+// The path of the file after the change, or before it if the file was deleted
+func (self DiffFile) Path() string {
+	if self.NewPath == "/dev/null" {
+		return self.OldPath
+	}
+	return self.NewPath
+}
+
+func (self DiffFile) Additions() int {
+	additions := 0
+	for _, hunk := range self.Hunks {
+		additions += hunk.Additions
+	}
+	return additions
+}
+
+func (self DiffFile) Deletions() int {
+	deletions := 0
+	for _, hunk := range self.Hunks {
+		deletions += hunk.Deletions
+	}
+	return deletions
+}
+
+// "@@ -1,2 +1,3 @@" in the same format as git
+func (self DiffHunk) Header() string {
+	old := strconv.Itoa(self.OldStart)
+	if self.OldLines != 1 {
+		old += "," + strconv.Itoa(self.OldLines)
+	}
+	updated := strconv.Itoa(self.NewStart)
+	if self.NewLines != 1 {
+		updated += "," + strconv.Itoa(self.NewLines)
+	}
+
+	return fmt.Sprintf("@@ -%s +%s @@", old, updated)
+}
+
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@ ?(.*)$`)
+
+func parseHunkHeader(line string) (DiffHunk, bool) {
+	match := hunkHeaderPattern.FindStringSubmatch(line)
+	if match == nil {
+		return DiffHunk{}, false
+	}
+
+	atoi := func(s string) int {
+		if s == "" {
+			// The number of lines is omitted if it is 1
+			return 1
+		}
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+
+	return DiffHunk{
+		OldStart: atoi(match[1]),
+		OldLines: atoi(match[2]),
+		NewStart: atoi(match[3]),
+		NewLines: atoi(match[4]),
+		Section:  match[5],
+	}, true
+}
+
+func (self *Message) parseDiff() {
+	if self.Scissors == -1 {
+		return
+	}
+
+	// Whether we are between the "diff --git" line and the first hunk of a file
+	inFileHeader := false
+	// Lines left in the current hunk
+	oldLeft, newLeft := 0, 0
+
+	for i := self.Scissors + 1; i < len(self.Lines); i++ {
+		line := self.Lines[i]
+
+		if oldLeft > 0 || newLeft > 0 {
+			file := &self.Diff[len(self.Diff)-1]
+			hunk := &file.Hunks[len(file.Hunks)-1]
+
+			switch {
+			case strings.HasPrefix(line, "+"):
+				hunk.Additions++
+				newLeft--
+			case strings.HasPrefix(line, "-"):
+				hunk.Deletions++
+				oldLeft--
+			case strings.HasPrefix(line, `\`):
+				// "\ No newline at end of file"
+			default:
+				oldLeft--
+				newLeft--
+			}
+
+			hunk.Range.End = self.lineEnd(i)
+			file.Range.End = hunk.Range.End
+			continue
+		}
+
+		if paths, ok := strings.CutPrefix(line, "diff --git "); ok {
+			// "diff --git a/old b/new". The "---" and "+++" lines below are more reliable, but
+			// there are none for binary files or mode changes
+			oldPath, newPath, _ := strings.Cut(paths, " b/")
+			self.Diff = append(self.Diff, DiffFile{
+				OldPath: strings.TrimPrefix(oldPath, "a/"),
+				NewPath: newPath,
+				Range: lsp.Range{
+					Start: lsp.Position{Line: i, Character: 0},
+					End:   self.lineEnd(i),
+				},
+			})
+			inFileHeader = true
+			continue
+		}
+		if len(self.Diff) == 0 {
+			continue
+		}
+
+		file := &self.Diff[len(self.Diff)-1]
+		if hunk, ok := parseHunkHeader(line); ok {
+			hunk.Range = lsp.Range{
+				Start: lsp.Position{Line: i, Character: 0},
+				End:   self.lineEnd(i),
+			}
+			file.Hunks = append(file.Hunks, hunk)
+			file.Range.End = hunk.Range.End
+			oldLeft, newLeft = hunk.OldLines, hunk.NewLines
+			inFileHeader = false
+			continue
+		}
+		if !inFileHeader {
+			continue
+		}
+
+		file.Range.End = self.lineEnd(i)
+		if path, ok := strings.CutPrefix(line, "--- "); ok {
+			file.OldPath = strings.TrimPrefix(path, "a/")
+		} else if path, ok := strings.CutPrefix(line, "+++ "); ok {
+			file.NewPath = strings.TrimPrefix(path, "b/")
+		}
+	}
+}
+
+// Position of the end of the line
+func (self *Message) lineEnd(line int) lsp.Position {
+	return lsp.Position{Line: line, Character: len(self.Lines[line])}
+}
+
+// Whether any line of the diff (including the hunk sections) contains the text
+func (self *Message) DiffContains(text string) bool {
+	for _, file := range self.Diff {
+		for _, hunk := range file.Hunks {
+			if strings.Contains(hunk.Section, text) {
+				return true
+			}
+			for line := hunk.Range.Start.Line + 1; line <= hunk.Range.End.Line; line++ {
+				if strings.Contains(self.Lines[line], text) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// Whether a file in the diff matches the path, which may be relative to any directory
+// (e.g. "state.go" matches "analysis/state.go")
+func (self *Message) DiffHasFile(filePath string) bool {
+	filePath = strings.TrimPrefix(filePath, "./")
+	return slices.ContainsFunc(self.Diff, func(file DiffFile) bool {
+		for _, p := range []string{file.OldPath, file.NewPath} {
+			if p == filePath || strings.HasSuffix(p, "/"+filePath) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Scopes suggested by the paths of the changed files, most common first
+func (self *Message) DiffScopes() []string {
+	paths := make([]string, len(self.Diff))
+	for i, file := range self.Diff {
+		paths[i] = file.Path()
+	}
+	return PathScopes(paths)
+}
+
+// The scopes that a path suggests: the first and last directory (e.g. "analysis" for
+// "analysis/state.go", and "lsp" and "handlers" for "lsp/handlers/hover.go")
+func pathScopes(filePath string) []string {
+	dir := path.Dir(filePath)
+	if dir == "." {
+		return nil
+	}
+
+	parts := strings.Split(dir, "/")
+	return slices.Compact([]string{parts[0], parts[len(parts)-1]})
+}
+
+// Scopes suggested by the paths, most common first
+func PathScopes(paths []string) []string {
+	counts := map[string]int{}
+	scopes := []string{}
+	for _, filePath := range paths {
+		for _, scope := range pathScopes(filePath) {
+			if counts[scope] == 0 {
+				scopes = append(scopes, scope)
+			}
+			counts[scope]++
+		}
+	}
+
+	slices.SortStableFunc(scopes, func(a, b string) int {
+		return counts[b] - counts[a]
+	})
+
+	return scopes
+}
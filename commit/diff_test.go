@@ -0,0 +1,101 @@
+package commit
+
+import (
+	"testing"
+
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const verboseMessage = "feat: add parse() to state.go\n" +
+	"\n" +
+	"Also touches lua/min_init.lua, see https://example.com/x.go\n" +
+	ScissorsLine + "\n" +
+	"diff --git a/analysis/state.go b/analysis/state.go\n" +
+	"--- a/analysis/state.go\n" +
+	"+++ b/analysis/state.go\n" +
+	"@@ -10,3 +10,4 @@ func parse() {\n" +
+	" a\n" +
+	"-b\n" +
+	"+c\n" +
+	"+d\n" +
+	" e\n" +
+	"diff --git a/lsp/handlers/hover.go b/lsp/handlers/hover.go\n" +
+	"new file mode 100644\n" +
+	"--- /dev/null\n" +
+	"+++ b/lsp/handlers/hover.go\n" +
+	"@@ -0,0 +1 @@\n" +
+	"+package handlers\n" +
+	"\\ No newline at end of file\n"
+
+func TestParseDiff(t *testing.T) {
+	msg := ParseMessage(verboseMessage)
+	require.Len(t, msg.Diff, 2)
+
+	file := msg.Diff[0]
+	assert.Equal(t, "analysis/state.go", file.OldPath)
+	assert.Equal(t, "analysis/state.go", file.Path())
+	assert.Equal(t, lsp.Range{
+		Start: lsp.Position{Line: 4, Character: 0},
+		End:   lsp.Position{Line: 12, Character: 2},
+	}, file.Range)
+	assert.Equal(t, []DiffHunk{{
+		OldStart:  10,
+		OldLines:  3,
+		NewStart:  10,
+		NewLines:  4,
+		Section:   "func parse() {",
+		Additions: 2,
+		Deletions: 1,
+		Range: lsp.Range{
+			Start: lsp.Position{Line: 7, Character: 0},
+			End:   lsp.Position{Line: 12, Character: 2},
+		},
+	}}, file.Hunks)
+	assert.Equal(t, "@@ -10,3 +10,4 @@", file.Hunks[0].Header())
+
+	file = msg.Diff[1]
+	assert.Equal(t, "/dev/null", file.OldPath)
+	assert.Equal(t, "lsp/handlers/hover.go", file.Path())
+	assert.Equal(t, 1, file.Additions())
+	assert.Equal(t, 0, file.Deletions())
+	assert.Equal(t, "@@ -0,0 +1 @@", file.Hunks[0].Header())
+
+	assert.True(t, msg.DiffContains("+c"))
+	assert.False(t, msg.DiffContains("No newline"))
+	assert.True(t, msg.DiffHasFile("state.go"))
+	assert.True(t, msg.DiffHasFile("handlers/hover.go"))
+	assert.False(t, msg.DiffHasFile("ate.go"))
+	assert.Equal(t, []string{"analysis", "lsp", "handlers"}, msg.DiffScopes())
+}
+
+func TestParseDiffWithoutScissors(t *testing.T) {
+	assert.Empty(t, ParseMessage("feat: x\n\ndiff --git a/x b/x\n@@ -1 +1 @@\n-a\n+b").Diff)
+}
+
+func TestDiffRules(t *testing.T) {
+	diagnostics := Check(&Context{Message: ParseMessage(verboseMessage)})
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(2, 13, 29),
+		Type:  FileNotInDiffWarning,
+		Args:  []string{"lua/min_init.lua"},
+	}}, diagnostics)
+
+	diagnostics = Check(&Context{Message: ParseMessage("fix: call State.hover() and `api.go`" + verboseMessage[29:])})
+	assert.Equal(t, []Diagnostic{
+		{Range: helper.LineRange(0, 29, 35), Type: FileNotInDiffWarning, Args: []string{"api.go"}},
+		{Range: helper.LineRange(2, 13, 29), Type: FileNotInDiffWarning, Args: []string{"lua/min_init.lua"}},
+		{Range: helper.LineRange(0, 10, 23), Type: FunctionNotInDiffWarning, Args: []string{"State.hover"}},
+	}, diagnostics)
+
+	// Files without a directory must be in backticks, so that words like "Node.js" are not files
+	diagnostics = Check(&Context{Message: ParseMessage("fix: support Node.js in api.go" + verboseMessage[29:])})
+	assert.Equal(t, []Diagnostic{
+		{Range: helper.LineRange(2, 13, 29), Type: FileNotInDiffWarning, Args: []string{"lua/min_init.lua"}},
+	}, diagnostics)
+
+	// Without a diff there is nothing to compare with
+	assert.Empty(t, Check(&Context{Message: ParseMessage("fix: update main.go and run()")}))
+}
@@ -0,0 +1,80 @@
+package commit
+
+import (
+	"testing"
+
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/internal/testrepo"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueReference(t *testing.T) {
+	root := testrepo.New(t, "feat: a")
+	testrepo.Git(t, root, "checkout", "--quiet", "-b", "feature/PROJ-123-foo")
+
+	cfg := config.Default()
+	check := func(text string, repo Repo) []Diagnostic {
+		return Check(&Context{Message: ParseMessage(text), Repo: repo, Config: cfg})
+	}
+
+	// Disabled by default
+	assert.Empty(t, check("feat: x", Repo{Root: root}))
+
+	cfg.IssueReference = config.IssueReferenceConfig{Required: true, Pattern: "PROJ-[0-9]+"}
+	assert.Empty(t, check("feat(PROJ-1): x", Repo{Root: root}))
+	assert.Empty(t, check("feat: fix PROJ-1", Repo{Root: root}))
+	assert.Empty(t, check("feat: x\n\nBody\n\nRefs: PROJ-1", Repo{Root: root}))
+	assert.Empty(t, check("Merge branch 'main'", Repo{Root: root}))
+
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(0, 0, 7),
+		Type:  MissingIssueReferenceError,
+		Args:  []string{"PROJ-123"},
+		Fixes: []Fix{{
+			Title: "Add 'Refs: PROJ-123'",
+			Edits: []lsp.TextEdit{{Range: helper.LineRange(4, 16, 16), NewText: "\nRefs: PROJ-123"}},
+		}},
+	}}, check("feat: x\n\nBody\n\nRefs: #1 OTHER-2", Repo{Root: root}))
+
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(0, 0, 7),
+		Type:  MissingIssueReferenceError,
+		Args:  []string{"PROJ-123"},
+		Fixes: []Fix{{
+			Title: "Add 'Refs: PROJ-123'",
+			Edits: []lsp.TextEdit{{Range: helper.LineRange(2, 27, 27), NewText: "\nRefs: PROJ-123"}},
+		}},
+	}}, check("feat: x\n\nAcked-by: A <a@example.com>", Repo{Root: root}))
+
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(0, 0, 7),
+		Type:  MissingIssueReferenceError,
+		Args:  []string{"PROJ-123"},
+		Fixes: []Fix{{
+			Title: "Add 'Refs: PROJ-123'",
+			Edits: []lsp.TextEdit{{Range: helper.LineRange(2, 4, 4), NewText: "\n\nRefs: PROJ-123"}},
+		}},
+	}}, check("feat: x\n\nBody", Repo{Root: root}))
+
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(0, 0, 7),
+		Type:  MissingIssueReferenceError,
+		Args:  []string{""},
+	}}, check("feat: x", Repo{}))
+
+	// The " #" separator of the spec's footers is part of the reference
+	cfg.IssueReference.Pattern = config.DefaultIssuePattern
+	assert.Empty(t, check("fix: x\n\nRefs #123", Repo{}))
+	assert.Empty(t, check("fix: x\n\nCloses #7", Repo{}))
+	assert.NotEmpty(t, check("fix: x\n\nAcked-by #7", Repo{}))
+
+	// Names of standards are not references
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(0, 0, 23),
+		Type:  MissingIssueReferenceError,
+		Args:  []string{""},
+	}}, check("fix: handle UTF-8 names", Repo{}))
+	assert.NotEmpty(t, check("fix: use SHA-256", Repo{}))
+}
@@ -0,0 +1,84 @@
+package commit
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/internal/helper"
+)
+
+// Commits with a message generated by git (or written in the form git expects), which do not
+// follow the Conventional Commits format
+type Kind string
+
+const (
+	// A commit that follows the Conventional Commits format
+	NormalKind Kind = ""
+	// `git revert` (`Revert "feat: x"`), or a "revert" type (`revert: feat: x`)
+	RevertKind Kind = "revert"
+	// `git merge` (e.g. "Merge branch 'main'")
+	MergeKind Kind = "merge"
+	// `git commit --fixup` ("fixup! feat: x")
+	FixupKind Kind = "fixup"
+	// `git commit --squash` ("squash! feat: x")
+	SquashKind Kind = "squash"
+	// `git commit --fixup=amend:` ("amend! feat: x")
+	AmendKind Kind = "amend"
+)
+
+var revertHeaderPattern = regexp.MustCompile(`^Revert "(.*)"$`)
+var mergeHeaderPattern = regexp.MustCompile(`^Merge (branch|branches|remote-tracking branch|tag|commit|pull request) `)
+
+// Prefixes that `git rebase --autosquash` recognizes
+var autosquashPrefixes = map[string]Kind{
+	"fixup! ":  FixupKind,
+	"squash! ": SquashKind,
+	"amend! ":  AmendKind,
+}
+
+// Recognize the headers generated by git. Returns false if the header is not one of them, and
+// should be parsed as a Conventional Commits header
+func (self *Message) parseGitHeader() bool {
+	header := &self.Header
+	text := header.Text
+
+	if match := revertHeaderPattern.FindStringSubmatchIndex(text); match != nil {
+		self.Commit.Kind = RevertKind
+		self.Commit.Target = text[match[2]:match[3]]
+		header.TargetRange = helper.LineRange(0, match[2], match[3])
+	} else if mergeHeaderPattern.MatchString(text) {
+		self.Commit.Kind = MergeKind
+	} else if kind, target, ok := cutAutosquashPrefix(text); ok {
+		self.Commit.Kind = kind
+		self.Commit.Target = target
+		header.TargetRange = helper.LineRange(0, len(text)-len(target), len(text))
+	} else {
+		return false
+	}
+
+	// The whole header is the description, so that it is not mistaken for a type/scope
+	self.Commit.Description = text
+	header.DescriptionRange = helper.LineRange(0, 0, len(text))
+	return true
+}
+
+func cutAutosquashPrefix(text string) (Kind, string, bool) {
+	for prefix, kind := range autosquashPrefixes {
+		if target, ok := strings.CutPrefix(text, prefix); ok {
+			return kind, target, true
+		}
+	}
+	return NormalKind, "", false
+}
+
+// The subject of the commit that an autosquash target refers to, without nested prefixes
+// (e.g. "feat: x" for "fixup! fixup! feat: x")
+func autosquashSubject(target string) string {
+	for {
+		_, rest, ok := cutAutosquashPrefix(target)
+		if !ok {
+			return target
+		}
+		target = rest
+	}
+}
@@ -0,0 +1,87 @@
+package commit
+
+import (
+	"testing"
+
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/internal/testrepo"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKind(t *testing.T) {
+	tests := []struct {
+		header string
+		kind   Kind
+		target string
+	}{
+		{`Revert "feat(api): add endpoint"`, RevertKind, "feat(api): add endpoint"},
+		{`Revert "Revert "feat: x""`, RevertKind, `Revert "feat: x"`},
+		{"revert: feat: x", RevertKind, "feat: x"},
+		{"Merge branch 'main' into feature", MergeKind, ""},
+		{"Merge pull request #12 from owner/branch", MergeKind, ""},
+		{"fixup! feat: x", FixupKind, "feat: x"},
+		{"squash! fixup! feat: x", SquashKind, "fixup! feat: x"},
+		{"amend! feat: x", AmendKind, "feat: x"},
+		{"feat: merge branch handling", NormalKind, ""},
+		{"Merge the tests", NormalKind, ""},
+	}
+
+	for _, test := range tests {
+		commit, diagnostics := Parse(test.header)
+		assert.Equal(t, test.kind, commit.Kind, test.header)
+		assert.Equal(t, test.target, commit.Target, test.header)
+		if test.kind != NormalKind && test.kind != RevertKind {
+			assert.Empty(t, diagnostics, test.header)
+		}
+	}
+
+	msg := ParseMessage(`Revert "feat: x"`)
+	assert.Equal(t, helper.LineRange(0, 8, 15), msg.Header.TargetRange)
+	assert.False(t, msg.Header.HasTypeScope)
+	assert.Equal(t, "", msg.Commit.Type)
+}
+
+func TestRevertWithoutCommit(t *testing.T) {
+	_, diagnostics := Parse("Revert \"feat: x\"\n\nThis reverts commit 1a2b3c4d.")
+	assert.Empty(t, diagnostics)
+
+	_, diagnostics = Parse("revert: feat: x\n\nRefs: 1a2b3c4d")
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(0, 0, 15),
+		Type:  RevertWithoutCommitWarning,
+		Args:  []string{"feat: x"},
+	}}, diagnostics)
+}
+
+func TestKindRulesInRepo(t *testing.T) {
+	root := testrepo.New(t, "feat(api): add endpoint")
+	hash := testrepo.Git(t, root, "rev-parse", "HEAD")
+
+	check := func(text string) []Diagnostic {
+		return Check(&Context{Message: ParseMessage(text), Repo: Repo{Root: root}})
+	}
+
+	assert.Empty(t, check("fixup! feat(api): add endpoint"))
+	assert.Empty(t, check("squash! feat(api): add"))
+	assert.Empty(t, check("fixup! fixup! feat(api): add endpoint"))
+	assert.Empty(t, check("amend! "+hash[:7]))
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(0, 7, 20),
+		Type:  UnknownAutosquashTargetWarning,
+		Args:  []string{"feat: unknown"},
+	}}, check("fixup! feat: unknown"))
+
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(0, 0, 32),
+		Type:  RevertWithoutCommitWarning,
+		Args:  []string{"feat(api): add endpoint"},
+		Fixes: []Fix{{
+			Title: "Insert \"This reverts commit " + hash[:7] + ".\"",
+			Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 32, 32), NewText: "\n\nThis reverts commit " + hash + "."}},
+		}},
+	}}, check(`Revert "feat(api): add endpoint"`))
+
+	// Without a repository, the targets can't be checked
+	assert.Empty(t, Check(&Context{Message: ParseMessage("fixup! feat: unknown")}))
+}
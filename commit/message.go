@@ -0,0 +1,356 @@
+package commit
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// Git adds comment lines (starting with the comment character) to the message, which are removed
+// before committing. Everything after the scissors line is removed too (e.g. the diff added by
+// `git commit --verbose`)
+const CommentChar = "#"
+const ScissorsLine = "# ------------------------ >8 ------------------------"
+
+// A parsed commit message, with the position of each of its parts
+//
+// ParseMessage never fails: whatever can be parsed is recorded here, and it is up to the rules
+// to report what is wrong with it
+type Message struct {
+	Commit Commit
+
+	// Every line of the text, without line endings
+	Lines []string
+
+	Header Header
+
+	// Ranges of the paragraphs in the body (not including the footers)
+	Body []lsp.Range
+
+	Footers []Footer
+
+	// Line numbers of the comment lines (before the scissors line)
+	Comments []int
+
+	// Rules disabled by "# git-lsp-disable" comments
+	Suppressions []Suppression
+
+	// Line number of the scissors line, or -1 if there is none
+	Scissors int
+
+	// Files in the diff below the scissors line
+	Diff []DiffFile
+
+	// Line numbers of each paragraph after the header, including the footers
+	paragraphs [][]int
+}
+
+// The first line of the commit message: "type(scope)!: description"
+//
+// All indexes are byte offsets into Text, or -1 if the character is not present
+type Header struct {
+	Text string
+
+	// Whether the header has a type/scope prefix (i.e. there was a ':')
+	// If not, the whole header is the description
+	HasTypeScope bool
+
+	Colon  int
+	Bang   int
+	LParen int
+	RParen int
+
+	// End of the type/scope prefix, not including the "!"
+	PrefixEnd int
+
+	TypeRange  lsp.Range
+	ScopeRange lsp.Range
+	// Range of each of Commit.Scopes
+	ScopeRanges      []lsp.Range
+	DescriptionRange lsp.Range
+
+	// Range of Commit.Target, if it is in the header
+	TargetRange lsp.Range
+}
+
+// A footer (or trailer) in the last paragraph of the message: "key: value" or "key #value"
+type Footer struct {
+	Key   string
+	Value string
+	// ": " or " #"
+	Separator string
+
+	// Range of the whole footer, including continuation lines
+	Range      lsp.Range
+	KeyRange   lsp.Range
+	ValueRange lsp.Range
+}
+
+// The value as it is written. The " #" separator is part of the value (e.g. "#123" in
+// "Refs #123"), but is not included in Value
+func (self Footer) RawValue() string {
+	if self.Separator == " #" {
+		return "#" + self.Value
+	}
+	return self.Value
+}
+
+func ParseMessage(text string) *Message {
+	msg := &Message{
+		Lines:    strings.Split(text, "\n"),
+		Scissors: -1,
+	}
+	for i, line := range msg.Lines {
+		msg.Lines[i] = strings.TrimSuffix(line, "\r")
+	}
+
+	msg.parseHeader()
+	msg.parseBodyAndFooters()
+	msg.parseDiff()
+
+	return msg
+}
+
+// Whether the line is a comment (or after the scissors line), and will be removed by git
+func (self *Message) IsComment(line int) bool {
+	if self.Scissors != -1 && line >= self.Scissors {
+		return true
+	}
+	return line > 0 && strings.HasPrefix(self.Lines[line], CommentChar)
+}
+
+// Number of lines before the scissors line
+func (self *Message) End() int {
+	if self.Scissors != -1 {
+		return self.Scissors
+	}
+	return len(self.Lines)
+}
+
+// Whether the message has no text yet, other than comments
+func (self *Message) IsEmpty() bool {
+	for line := 0; line < self.End(); line++ {
+		if !self.IsComment(line) && strings.TrimSpace(self.Lines[line]) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (self *Message) parseHeader() {
+	header := &self.Header
+	header.Text = self.Lines[0]
+	header.Colon = -1
+	header.Bang = -1
+	header.LParen = -1
+	header.RParen = -1
+
+	if self.parseGitHeader() {
+		return
+	}
+
+	typeScope, description, foundTypeScope := strings.Cut(header.Text, ":")
+	if !foundTypeScope {
+		// Header line wasn't split, so typeScope is the whole line, which we will use as the description
+		self.Commit.Description = typeScope
+		header.DescriptionRange = helper.LineRange(0, 0, len(header.Text))
+		return
+	}
+
+	header.HasTypeScope = true
+	header.Colon = len(typeScope)
+
+	trimmed := strings.TrimLeft(description, " \t")
+	start := header.Colon + 1 + len(description) - len(trimmed)
+	self.Commit.Description = strings.TrimSpace(description)
+	header.DescriptionRange = helper.LineRange(0, start, start+len(self.Commit.Description))
+
+	// Check for breaking change "!"
+	if idx := strings.LastIndex(typeScope, "!"); idx != -1 {
+		header.Bang = idx
+
+		// Remove "!"
+		typeScope = typeScope[:idx] // Check to make sure
+
+		// "13. If included in the type/scope prefix, breaking changes MUST be indicated by a ! immediately before the :. If ! is used, BREAKING CHANGE: MAY be omitted from the footer section, and the commit description SHALL be used to describe the breaking change."
+		// NOTE: I am interpreting the above requirement to mean that if the BREAKING CHANGE footer is included, that is used instead of the description
+		self.Commit.BreakingChange = self.Commit.Description
+	}
+	header.PrefixEnd = len(typeScope)
+
+	// Extract scope if present
+	if lParIdx := strings.Index(typeScope, "("); lParIdx != -1 {
+		header.LParen = lParIdx
+		self.Commit.Type = typeScope[:lParIdx]
+
+		scopeEnd := len(typeScope)
+		if rParIdx := strings.Index(typeScope[lParIdx:], ")"); rParIdx != -1 {
+			header.RParen = lParIdx + rParIdx
+			scopeEnd = header.RParen
+		}
+		self.Commit.Scope = typeScope[lParIdx+1 : scopeEnd]
+		header.ScopeRange = helper.LineRange(0, lParIdx+1, scopeEnd)
+		self.SplitScopes(config.DefaultScopeDelimiters)
+	} else if idx := strings.Index(typeScope, ")"); idx != -1 {
+		// There wasn't a '(', but there was a ')'
+		header.RParen = idx
+		self.Commit.Type = typeScope[:idx]
+	} else {
+		self.Commit.Type = typeScope
+	}
+	header.TypeRange = helper.LineRange(0, 0, len(self.Commit.Type))
+
+	if self.Commit.Type == "revert" {
+		self.Commit.Kind = RevertKind
+		self.Commit.Target = self.Commit.Description
+		header.TargetRange = header.DescriptionRange
+	}
+}
+
+// Split the scope at any of the delimiters into Commit.Scopes and Header.ScopeRanges
+//
+// ParseMessage splits at the default delimiters, so this only needs to be called again for
+// configured delimiters. Empty scopes (e.g. in "api,,ui") are left out
+func (self *Message) SplitScopes(delimiters string) {
+	self.Commit.Scopes = nil
+	self.Header.ScopeRanges = nil
+
+	scope := self.Commit.Scope
+	offset := self.Header.ScopeRange.Start.Character
+	isDelimiter := func(r rune) bool {
+		return strings.ContainsRune(delimiters, r)
+	}
+	for start := 0; start <= len(scope); {
+		// The delimiters may be any characters, not only single bytes
+		i, next := len(scope), len(scope)+1
+		if end := strings.IndexFunc(scope[start:], isDelimiter); end != -1 {
+			_, size := utf8.DecodeRuneInString(scope[start+end:])
+			i, next = start+end, start+end+size
+		}
+
+		part := scope[start:i]
+		trimmed := strings.TrimLeftFunc(part, unicode.IsSpace)
+		partStart := start + len(part) - len(trimmed)
+		trimmed = strings.TrimRightFunc(trimmed, unicode.IsSpace)
+		if trimmed != "" {
+			self.Commit.Scopes = append(self.Commit.Scopes, trimmed)
+			self.Header.ScopeRanges = append(self.Header.ScopeRanges, helper.LineRange(0, offset+partStart, offset+partStart+len(trimmed)))
+		}
+		start = next
+	}
+}
+
+func (self *Message) parseBodyAndFooters() {
+	for i, line := range self.Lines {
+		if i == 0 {
+			continue
+		}
+		if line == ScissorsLine {
+			self.Scissors = i
+			break
+		}
+		if self.IsComment(i) {
+			self.Comments = append(self.Comments, i)
+			self.Suppressions = append(self.Suppressions, parseSuppressions(i, line)...)
+		}
+	}
+
+	// Split the rest of the message into paragraphs, separated by blank lines
+	paragraphs := [][]int{}
+	var paragraph []int
+	for i := 1; i < self.End(); i++ {
+		if self.IsComment(i) {
+			continue
+		}
+
+		if strings.TrimSpace(self.Lines[i]) == "" {
+			if paragraph != nil {
+				paragraphs = append(paragraphs, paragraph)
+				paragraph = nil
+			}
+			continue
+		}
+		paragraph = append(paragraph, i)
+	}
+	if paragraph != nil {
+		paragraphs = append(paragraphs, paragraph)
+	}
+	self.paragraphs = paragraphs
+
+	// "8. One or more footers MAY be provided one blank line after the body."
+	// Only the last paragraph can contain footers, and only if it starts with one
+	if len(paragraphs) > 0 {
+		last := paragraphs[len(paragraphs)-1]
+		if _, _, ok := ParseFooter(self.Lines[last[0]]); ok {
+			self.parseFooters(last)
+			paragraphs = paragraphs[:len(paragraphs)-1]
+		}
+	}
+
+	bodyLines := []string{}
+	for i, paragraph := range paragraphs {
+		if i > 0 {
+			bodyLines = append(bodyLines, "")
+		}
+
+		for _, line := range paragraph {
+			bodyLines = append(bodyLines, self.Lines[line])
+		}
+
+		first, last := paragraph[0], paragraph[len(paragraph)-1]
+		self.Body = append(self.Body, lsp.Range{
+			Start: lsp.Position{Line: first, Character: 0},
+			End:   lsp.Position{Line: last, Character: len(self.Lines[last])},
+		})
+	}
+	self.Commit.Body = strings.Join(bodyLines, "\n")
+}
+
+func (self *Message) parseFooters(lines []int) {
+	footers := map[string]string{}
+
+	for _, line := range lines {
+		text := self.Lines[line]
+
+		key, value, ok := ParseFooter(text)
+		if !ok && len(self.Footers) > 0 {
+			// "10. A footer's value MAY contain spaces and newlines, and parsing MUST terminate when the next valid footer token/separator pair is observed."
+			footer := &self.Footers[len(self.Footers)-1]
+			footer.Value += "\n" + strings.TrimSpace(text)
+			footer.Range.End = lsp.Position{Line: line, Character: len(text)}
+			footer.ValueRange.End = footer.Range.End
+			continue
+		}
+
+		separator := ": "
+		if !strings.HasPrefix(text[len(key):], separator) {
+			separator = " #"
+		}
+		valueStart := len(key) + len(separator)
+		valueStart += len(text[valueStart:]) - len(strings.TrimLeft(text[valueStart:], " \t"))
+
+		self.Footers = append(self.Footers, Footer{
+			Key:        key,
+			Value:      value,
+			Separator:  separator,
+			Range:      helper.LineRange(line, 0, len(text)),
+			KeyRange:   helper.LineRange(line, 0, len(key)),
+			ValueRange: helper.LineRange(line, valueStart, valueStart+len(value)),
+		})
+	}
+
+	for _, footer := range self.Footers {
+		if _, ok := footers[footer.Key]; !ok {
+			footers[footer.Key] = footer.Value
+		}
+
+		if footer.Key == "BREAKING CHANGE" || footer.Key == "BREAKING-CHANGE" {
+			self.Commit.BreakingChange = footer.Value
+		}
+	}
+	self.Commit.Footers = footers
+}
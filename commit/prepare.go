@@ -0,0 +1,154 @@
+package commit
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/git"
+)
+
+// Sources of a commit message, as git passes them to the prepare-commit-msg hook
+const (
+	// `git commit` without a message: the message is only git's comments
+	SourceNone = ""
+	// `git commit -m` or `-F`
+	SourceMessage = "message"
+	// `git commit -t`, or commit.template is set
+	SourceTemplate = "template"
+	// A merge, or a message in .git/MERGE_MSG
+	SourceMerge = "merge"
+	// A message in .git/SQUASH_MSG
+	SourceSquash = "squash"
+	// `git commit --amend`, `-c` or `-C`: the message of an existing commit
+	SourceCommit = "commit"
+)
+
+// Whether the path matches a pattern of typePatterns
+func matchTypePattern(pattern string, filePath string) bool {
+	if dir, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return strings.HasPrefix(filePath, dir+"/")
+	}
+	if !strings.Contains(pattern, "/") {
+		filePath = path.Base(filePath)
+	}
+
+	matched, _ := path.Match(pattern, filePath)
+	return matched
+}
+
+// The type of the files, or "" if they are of different types or any of them has no type
+func InferType(cfg *config.Config, paths []string) string {
+	inferred := ""
+	for _, filePath := range paths {
+		i := slices.IndexFunc(cfg.TypePatterns, func(pattern config.TypePattern) bool {
+			return slices.ContainsFunc(pattern.Paths, func(p string) bool {
+				return matchTypePattern(p, filePath)
+			})
+		})
+		if i == -1 || (inferred != "" && cfg.TypePatterns[i].Type != inferred) {
+			return ""
+		}
+		inferred = cfg.TypePatterns[i].Type
+	}
+
+	if !cfg.HasType(inferred) {
+		return ""
+	}
+	return inferred
+}
+
+// The scope of all of the files, or "" if they have nothing in common
+//
+// A scope of scopePaths is used if it contains all of the files. Otherwise, this is a directory
+// that all of the paths suggest (see PathScopes), if it is an allowed scope
+func InferScope(cfg *config.Config, paths []string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+
+	scopes := []string{}
+	for scope := range cfg.ScopePaths {
+		scopes = append(scopes, scope)
+	}
+	slices.Sort(scopes)
+	for _, scope := range scopes {
+		if !slices.ContainsFunc(paths, func(filePath string) bool {
+			return !slices.ContainsFunc(cfg.ScopePaths[scope], func(scopePath string) bool {
+				scopePath = strings.TrimSuffix(path.Clean(scopePath), "/")
+				return filePath == scopePath || strings.HasPrefix(filePath, scopePath+"/")
+			})
+		}) {
+			return scope
+		}
+	}
+
+	for _, scope := range PathScopes(paths) {
+		inAll := !slices.ContainsFunc(paths, func(filePath string) bool {
+			return !slices.Contains(pathScopes(filePath), scope)
+		})
+		if inAll && (len(cfg.Scopes) == 0 || slices.Contains(cfg.Scopes, scope)) && !slices.Contains(cfg.DeprecatedScopes, scope) {
+			return scope
+		}
+	}
+	return ""
+}
+
+// Comment lines that list the staged changes, like `git status` does
+func changesSummary(changes []git.Change) []string {
+	lines := []string{CommentChar + " Staged changes:"}
+	for _, change := range changes {
+		file := change.Path
+		if change.OldPath != "" {
+			file = change.OldPath + " -> " + change.Path
+		}
+		lines = append(lines, fmt.Sprintf("%s\t%-12s%s", CommentChar, change.StatusText()+":", file))
+	}
+	return lines
+}
+
+// Fill in the message that git prepared for `git commit` (text), with a header inferred from the
+// staged changes, a trailer with the ticket of the branch and a summary of the changes
+//
+// Returns false if the message should be left as it is: if it comes from a merge, a squash, an
+// existing commit or the command line, or already has text other than comments
+func Prepare(repo Repo, cfg *config.Config, text string, source string, changes []git.Change) (string, bool) {
+	if source != SourceNone && source != SourceTemplate {
+		return text, false
+	}
+	if !ParseMessage(text).IsEmpty() {
+		return text, false
+	}
+
+	paths := make([]string, len(changes))
+	for i, change := range changes {
+		paths[i] = change.Path
+	}
+
+	header := ""
+	if commitType := InferType(cfg, paths); commitType != "" {
+		header = commitType
+		// A scope that repeats the type (e.g. "docs(docs)") says nothing
+		if scope := InferScope(cfg, paths); scope != "" && scope != commitType {
+			header += "(" + scope + ")"
+		}
+		header += ": "
+	}
+
+	lines := []string{header, ""}
+	if ticket := BranchTicket(repo, cfg); ticket != "" {
+		lines = append(lines, fmt.Sprintf("%s: %s", RefsKey, ticket), "")
+	}
+
+	// The comments that git wrote (the first line of its message is always empty)
+	rest := strings.TrimLeft(text, "\n")
+	if len(changes) > 0 {
+		lines = append(lines, changesSummary(changes)...)
+		if rest != "" {
+			lines = append(lines, CommentChar)
+		}
+	}
+	return strings.Join(lines, "\n") + "\n" + rest, true
+}
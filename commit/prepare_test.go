@@ -0,0 +1,76 @@
+package commit
+
+import (
+	"testing"
+
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/git"
+	"github.com/eamonburns/git-lsp/internal/testrepo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInferType(t *testing.T) {
+	cfg := config.Default()
+	assert.Equal(t, "test", InferType(cfg, []string{"commit/prepare_test.go", "testdata/a.txt"}))
+	assert.Equal(t, "docs", InferType(cfg, []string{"README.md", "docs/guide/setup.html"}))
+	assert.Equal(t, "ci", InferType(cfg, []string{".github/workflows/go.yml"}))
+	assert.Equal(t, "", InferType(cfg, []string{"commit/prepare.go"}))
+	assert.Equal(t, "", InferType(cfg, []string{"commit/prepare_test.go", "README.md"}))
+
+	cfg.Types = []config.CommitType{{Name: "feat"}}
+	assert.Equal(t, "", InferType(cfg, []string{"README.md"}))
+}
+
+func TestInferScope(t *testing.T) {
+	cfg := config.Default()
+	assert.Equal(t, "analysis", InferScope(cfg, []string{"analysis/state.go", "analysis/snippets.go"}))
+	assert.Equal(t, "handlers", InferScope(cfg, []string{"lsp/handlers/hover.go", "api/handlers/get.go"}))
+	assert.Equal(t, "", InferScope(cfg, []string{"analysis/state.go", "main.go"}))
+
+	cfg.Scopes = []string{"lsp"}
+	assert.Equal(t, "", InferScope(cfg, []string{"analysis/state.go"}))
+
+	cfg.ScopePaths = map[string][]string{"server": {"analysis", "main.go"}}
+	assert.Equal(t, "server", InferScope(cfg, []string{"analysis/state.go", "main.go"}))
+}
+
+func TestPrepare(t *testing.T) {
+	root := testrepo.Init(t)
+	testrepo.Git(t, root, "checkout", "--quiet", "-b", "feature/PROJ-7-tests")
+
+	cfg := config.Default()
+	changes := []git.Change{
+		{Status: 'A', Path: "commit/prepare_test.go"},
+		{Status: 'R', Path: "commit/diff_test.go", OldPath: "commit/old_test.go"},
+	}
+	gitMessage := "\n# Please enter the commit message for your changes.\n"
+
+	text, ok := Prepare(Repo{Root: root}, cfg, gitMessage, SourceNone, changes)
+	assert.True(t, ok)
+	assert.Equal(t, "test(commit): \n"+
+		"\n"+
+		"Refs: PROJ-7\n"+
+		"\n"+
+		"# Staged changes:\n"+
+		"#\tnew file:   commit/prepare_test.go\n"+
+		"#\trenamed:    commit/old_test.go -> commit/diff_test.go\n"+
+		"#\n"+
+		"# Please enter the commit message for your changes.\n", text)
+
+	text, _ = Prepare(Repo{}, cfg, "", SourceNone, []git.Change{{Status: 'M', Path: "docs/setup.md"}})
+	assert.Equal(t, "docs: \n\n# Staged changes:\n#\tmodified:   docs/setup.md\n", text)
+
+	text, ok = Prepare(Repo{}, cfg, "", SourceTemplate, []git.Change{{Status: 'M', Path: "main.go"}})
+	assert.True(t, ok)
+	assert.Equal(t, "\n\n# Staged changes:\n#\tmodified:   main.go\n", text)
+
+	for _, source := range []string{SourceMessage, SourceMerge, SourceSquash, SourceCommit} {
+		text, ok = Prepare(Repo{Root: root}, cfg, gitMessage, source, changes)
+		assert.False(t, ok, source)
+		assert.Equal(t, gitMessage, text)
+	}
+
+	// A template with text
+	_, ok = Prepare(Repo{Root: root}, cfg, "feat: \n"+gitMessage, SourceTemplate, changes)
+	assert.False(t, ok)
+}
@@ -0,0 +1,129 @@
+package commit
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+type ReferenceKind int
+
+const (
+	// An issue or pull request (e.g. "#123", "PROJ-42")
+	IssueReference ReferenceKind = iota
+	// A URL (e.g. "https://example.com")
+	URLReference
+	// Something that looks like a (possibly abbreviated) commit hash (e.g. "1a2b3c4"). Whether
+	// the commit exists can only be checked in the repository
+	CommitReference
+)
+
+// Something in the commit message that refers to something outside of it
+type Reference struct {
+	Kind  ReferenceKind
+	Text  string
+	Range lsp.Range
+}
+
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"'()\[\]]+`)
+
+// 7 to 40 hexadecimal digits, with at least one digit so that words like "defaced" are ignored
+var commitPattern = regexp.MustCompile(`(?:^|[^\w#/-])([0-9a-f]{7,40})\b`)
+var digitPattern = regexp.MustCompile(`[0-9]`)
+
+func isWordByte(c byte) bool {
+	return c == '_' || ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// Whether the match is not part of a word or path (e.g. "abc#123", "a/#3" or "#12ab")
+func isWholeReference(text string, start int, end int) bool {
+	if start > 0 && (isWordByte(text[start-1]) || strings.IndexByte("#/-", text[start-1]) != -1) {
+		return false
+	}
+	return end == len(text) || !isWordByte(text[end])
+}
+
+// Find the references in a single line. Issue references are matches of the issue pattern (see
+// config.Config.IssuePattern), except for names like "UTF-8" (see config.IsIssueReference)
+func FindReferences(line int, text string, issuePattern *regexp.Regexp) []Reference {
+	references := []Reference{}
+
+	urls := urlPattern.FindAllStringIndex(text, -1)
+	for _, match := range urls {
+		// Punctuation at the end of a URL is most likely part of the sentence
+		url := strings.TrimRight(text[match[0]:match[1]], ".,;:!?")
+		references = append(references, Reference{
+			Kind:  URLReference,
+			Text:  url,
+			Range: helper.LineRange(line, match[0], match[0]+len(url)),
+		})
+	}
+
+	insideURL := func(start int) bool {
+		for _, url := range urls {
+			if start >= url[0] && start < url[1] {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, match := range issuePattern.FindAllStringIndex(text, -1) {
+		start, end := match[0], match[1]
+		if start == end || insideURL(start) || !isWholeReference(text, start, end) || !config.IsIssueReference(text[start:end]) {
+			continue
+		}
+
+		references = append(references, Reference{
+			Kind:  IssueReference,
+			Text:  text[start:end],
+			Range: helper.LineRange(line, start, end),
+		})
+	}
+
+	for _, match := range commitPattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := match[2], match[3]
+		if insideURL(start) || !digitPattern.MatchString(text[start:end]) {
+			continue
+		}
+
+		references = append(references, Reference{
+			Kind:  CommitReference,
+			Text:  text[start:end],
+			Range: helper.LineRange(line, start, end),
+		})
+	}
+
+	return references
+}
+
+// Find the references in the header description, body and footers
+func (self *Message) References(cfg *config.Config) []Reference {
+	issuePattern := cfg.IssuePattern()
+	references := []Reference{}
+
+	for line := 0; line < self.End(); line++ {
+		if self.IsComment(line) {
+			continue
+		}
+
+		text := self.Lines[line]
+		offset := 0
+		if line == 0 {
+			// Only look in the description, so that the scope is not mistaken for an issue
+			offset = self.Header.DescriptionRange.Start.Character
+			text = text[:self.Header.DescriptionRange.End.Character]
+		}
+
+		for _, reference := range FindReferences(line, text, issuePattern) {
+			if reference.Range.Start.Character >= offset {
+				references = append(references, reference)
+			}
+		}
+	}
+
+	return references
+}
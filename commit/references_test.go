@@ -0,0 +1,45 @@
+package commit
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindReferences(t *testing.T) {
+	issuePattern := config.Default().IssuePattern()
+	assert.Equal(t, []Reference{
+		{Kind: URLReference, Text: "https://example.com/issues/4#x", Range: helper.LineRange(2, 22, 52)},
+		{Kind: IssueReference, Text: "#12", Range: helper.LineRange(2, 4, 7)},
+		{Kind: IssueReference, Text: "PROJ-42", Range: helper.LineRange(2, 9, 16)},
+	}, FindReferences(2, "Fix #12, PROJ-42. See https://example.com/issues/4#x.", issuePattern))
+
+	assert.Empty(t, FindReferences(0, "abc#12 utf-8 a/#3 #12ab UTF-8 SHA-256 X-1", issuePattern))
+
+	assert.Equal(t, []Reference{
+		{Kind: CommitReference, Text: "1a2b3c4", Range: helper.LineRange(1, 12, 19)},
+	}, FindReferences(1, "Reverts the 1a2b3c4 change, defaced, 1a2b3c, x/1a2b3c4d", issuePattern))
+
+	assert.Equal(t, []Reference{
+		{Kind: IssueReference, Text: "GH-7", Range: helper.LineRange(0, 4, 8)},
+	}, FindReferences(0, "Fix GH-7, not #8 or XGH-9", regexp.MustCompile(`GH-[0-9]+`)))
+}
+
+func TestMessageReferences(t *testing.T) {
+	msg := ParseMessage("fix(PROJ-1): crash (#3)\n\nSee ABC-9\n# Comment #4\n\nCloses #5")
+	assert.Equal(t, []Reference{
+		{Kind: IssueReference, Text: "#3", Range: helper.LineRange(0, 20, 22)},
+		{Kind: IssueReference, Text: "ABC-9", Range: helper.LineRange(2, 4, 9)},
+		{Kind: IssueReference, Text: "#5", Range: helper.LineRange(5, 7, 9)},
+	}, msg.References(config.Default()))
+
+	// Only the configured pattern is an issue reference
+	cfg := config.Default()
+	cfg.IssueReference.Pattern = "ABC-[0-9]+"
+	assert.Equal(t, []Reference{
+		{Kind: IssueReference, Text: "ABC-9", Range: helper.LineRange(2, 4, 9)},
+	}, msg.References(cfg))
+}
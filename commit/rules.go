@@ -0,0 +1,221 @@
+package commit
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// Information about a rule that can produce diagnostics
+type RuleInfo struct {
+	// Stable ID of the rule (e.g. "header/empty-scope")
+	ID DiagnosticType
+	// Short, human readable description of what the rule checks
+	Description string
+	// Severity of the diagnostics produced by the rule, unless configured otherwise
+	Severity lsp.DiagnosticSeverity
+	// Where to find more information about the rule
+	DocURL string
+	// The message of a diagnostic that has none of its own. If it is nil or returns "" (e.g. if
+	// the diagnostic does not have the arguments it needs), the description is used instead
+	Message func(diagnostic Diagnostic) string
+}
+
+// A message that does not depend on the diagnostic
+func staticMessage(message string) func(Diagnostic) string {
+	return func(Diagnostic) string {
+		return message
+	}
+}
+
+// A message formatted with the first n arguments of the diagnostic, or "" if it has fewer
+func formatMessage(format string, n int) func(Diagnostic) string {
+	return func(diagnostic Diagnostic) string {
+		if len(diagnostic.Args) < n {
+			return ""
+		}
+
+		args := []any{}
+		for _, arg := range diagnostic.Args[:n] {
+			args = append(args, arg)
+		}
+		return fmt.Sprintf(format, args...)
+	}
+}
+
+// A Rule checks a parsed commit message, and reports any problems it finds as diagnostics
+//
+// Rules are registered with Register, and are run by Check
+type Rule interface {
+	Info() RuleInfo
+	// The returned diagnostics should have their Type set to the ID of the rule
+	Check(ctx *Context) []Diagnostic
+}
+
+// Everything a rule knows about the commit message it is checking
+type Context struct {
+	Message *Message
+	Repo    Repo
+	// Configuration of the repository, or nil to use the default configuration
+	Config *config.Config
+
+	// The trailer separators of the repository, read once for all rules
+	separators string
+}
+
+func (self *Context) config() *config.Config {
+	if self.Config == nil {
+		self.Config = config.Default()
+	}
+	return self.Config
+}
+
+func (self *Context) trailerSeparators() string {
+	if self.separators == "" {
+		self.separators = TrailerSeparators(self.Repo)
+	}
+	return self.separators
+}
+
+// The repository a commit message belongs to
+type Repo struct {
+	// Root directory of the working tree, or "" if it is unknown (e.g. when linting stdin)
+	Root string
+}
+
+type ruleFunc struct {
+	info  RuleInfo
+	check func(ctx *Context) []Diagnostic
+}
+
+func (self ruleFunc) Info() RuleInfo {
+	return self.info
+}
+
+func (self ruleFunc) Check(ctx *Context) []Diagnostic {
+	return self.check(ctx)
+}
+
+// Create a rule from its info and a check function
+func NewRule(info RuleInfo, check func(ctx *Context) []Diagnostic) Rule {
+	return ruleFunc{info: info, check: check}
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []Rule
+	// Diagnostics that are reported outside of Check (e.g. by Suppress)
+	infoRegistry []RuleInfo
+)
+
+// Register a rule, so that it is run by Check
+//
+// Panics if a rule with the same ID is already registered. This is intended to be called from
+// an init function, so that git-lsp can be embedded as a library with custom rules
+func Register(rule Rule) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	checkUnregistered(rule.Info().ID)
+	registry = append(registry, rule)
+}
+
+// Register the info of diagnostics that are reported outside of Check, so that they are listed
+// and looked up like the rules without being run
+//
+// Panics if a rule with the same ID is already registered
+func RegisterInfo(info RuleInfo) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	checkUnregistered(info.ID)
+	infoRegistry = append(infoRegistry, info)
+}
+
+// Must be called with registryMu locked
+func checkUnregistered(id DiagnosticType) {
+	if slices.ContainsFunc(registry, func(r Rule) bool { return r.Info().ID == id }) ||
+		slices.ContainsFunc(infoRegistry, func(info RuleInfo) bool { return info.ID == id }) {
+		panic(fmt.Sprintf("commit: rule '%s' registered twice", id))
+	}
+}
+
+func registeredRules() []Rule {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return slices.Clone(registry)
+}
+
+// The info of all registered rules, and of the diagnostics registered with RegisterInfo
+func registeredInfos() []RuleInfo {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	infos := slices.Clone(infoRegistry)
+	for _, rule := range registry {
+		infos = append(infos, rule.Info())
+	}
+	return infos
+}
+
+// All registered rules, sorted by ID
+func Rules() []RuleInfo {
+	rules := registeredInfos()
+
+	slices.SortFunc(rules, func(a, b RuleInfo) int {
+		if a.ID < b.ID {
+			return -1
+		} else if a.ID > b.ID {
+			return 1
+		}
+		return 0
+	})
+
+	return rules
+}
+
+func LookupRule(id DiagnosticType) (RuleInfo, bool) {
+	for _, info := range registeredInfos() {
+		if info.ID == id {
+			return info, true
+		}
+	}
+
+	return RuleInfo{}, false
+}
+
+// Run all registered rules on the message
+//
+// The scopes of the message are split again at the configured delimiters. Rules that are
+// disabled in the configuration are not run, and the configured severities replace the ones of
+// the rules
+func Check(ctx *Context) []Diagnostic {
+	if ctx.Config != nil {
+		ctx.Message.SplitScopes(ctx.Config.ScopeDelimiters)
+	}
+
+	cfg := ctx.config()
+	diagnostics := []Diagnostic{}
+	for _, rule := range registeredRules() {
+		if _, enabled := cfg.RuleSeverity(string(rule.Info().ID)); !enabled {
+			continue
+		}
+
+		for _, diagnostic := range rule.Check(ctx) {
+			severity, enabled := cfg.RuleSeverity(string(diagnostic.Type))
+			if !enabled {
+				continue
+			}
+			if severity != 0 {
+				diagnostic.Severity = severity
+			}
+			diagnostics = append(diagnostics, diagnostic)
+		}
+	}
+
+	return diagnostics
+}
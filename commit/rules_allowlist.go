@@ -0,0 +1,285 @@
+package commit
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// Diagnostic error/warning types
+const (
+	// The type is one of the configured types, but in a different case (e.g. "Feat")
+	// Args: 0 = type, 1 = configured type
+	TypeCaseWarning DiagnosticType = "type/case"
+	// The type is not one of the configured types (e.g. "feta")
+	// Args: 0 = type
+	UnknownTypeWarning DiagnosticType = "type/unknown"
+	// The scope is one of the allowed scopes, but in a different case (e.g. "API")
+	// Args: 0 = scope, 1 = allowed scope
+	ScopeCaseWarning DiagnosticType = "scope/case"
+	// The scope is not one of the allowed scopes (only if scopes or scopesFromHistory are configured)
+	// Args: 0 = scope
+	UnknownScopeWarning DiagnosticType = "scope/unknown"
+	// There is whitespace in the scope (e.g. "fix(ui ): x")
+	ScopeWhitespaceWarning DiagnosticType = "scope/whitespace"
+)
+
+// Rules for the values of the type and scope, instead of their syntax
+
+func init() {
+	Register(NewRule(RuleInfo{
+		ID:          TypeCaseWarning,
+		Description: "The type must be in the same case as the configured type",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		Message:     formatMessage("Type '%s' should be '%s'", 2),
+	}, checkTypeCase))
+	Register(NewRule(RuleInfo{
+		ID:          UnknownTypeWarning,
+		Description: "The type must be one of the configured types",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		Message:     formatMessage("Unknown type '%s'", 1),
+	}, checkUnknownType))
+	Register(NewRule(RuleInfo{
+		ID:          ScopeCaseWarning,
+		Description: "The scope must be in the same case as the allowed scope",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		Message:     formatMessage("Scope '%s' should be '%s'", 2),
+	}, checkScopeCase))
+	Register(NewRule(RuleInfo{
+		ID:          UnknownScopeWarning,
+		Description: "The scope must be one of the configured scopes, or a scope used in the history (if scopesFromHistory is enabled)",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		Message:     formatMessage("Unknown scope '%s'", 1),
+	}, checkUnknownScope))
+	Register(NewRule(RuleInfo{
+		ID:          ScopeWhitespaceWarning,
+		Description: "The scope must not contain whitespace",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		Message:     staticMessage("Whitespace in scope"),
+	}, checkScopeWhitespace))
+}
+
+// Fixes that replace the text in the range with each suggestion
+func suggestionFixes(textRange lsp.Range, suggested []string) []Fix {
+	fixes := []Fix{}
+	for _, suggestion := range suggested {
+		fixes = append(fixes, Fix{
+			Title: fmt.Sprintf("Change to '%s'", suggestion),
+			Edits: []lsp.TextEdit{{Range: textRange, NewText: suggestion}},
+		})
+	}
+	return fixes
+}
+
+// The value, or a value in the list that only differs from it in case
+func findFold(values []string, value string) (string, bool) {
+	i := slices.IndexFunc(values, func(v string) bool {
+		return strings.EqualFold(v, value)
+	})
+	if i == -1 {
+		return "", false
+	}
+	return values[i], true
+}
+
+func typeNames(cfg *config.Config) []string {
+	names := []string{}
+	for _, commitType := range cfg.Types {
+		names = append(names, commitType.Name)
+	}
+	return names
+}
+
+// Whether the type of the message should be one of the configured types
+func hasCheckedType(msg *Message) bool {
+	header := msg.Header
+	if header.LParen == -1 && header.RParen != -1 {
+		// The scope is probably part of the type, which is reported by header/unmatched-right-paren
+		return false
+	}
+	return header.HasTypeScope && msg.Commit.Type != "" && (msg.Commit.Kind == NormalKind || msg.Commit.Kind == RevertKind)
+}
+
+func checkTypeCase(ctx *Context) []Diagnostic {
+	msg := ctx.Message
+	cfg := ctx.config()
+	if !hasCheckedType(msg) || cfg.HasType(msg.Commit.Type) {
+		return nil
+	}
+
+	configured, ok := findFold(typeNames(cfg), msg.Commit.Type)
+	if !ok {
+		return nil
+	}
+
+	return []Diagnostic{{
+		Range: msg.Header.TypeRange,
+		Type:  TypeCaseWarning,
+		Args:  []string{msg.Commit.Type, configured},
+		Fixes: suggestionFixes(msg.Header.TypeRange, []string{configured}),
+	}}
+}
+
+func checkUnknownType(ctx *Context) []Diagnostic {
+	msg := ctx.Message
+	cfg := ctx.config()
+	if !hasCheckedType(msg) || cfg.HasType(msg.Commit.Type) {
+		return nil
+	}
+
+	names := typeNames(cfg)
+	if _, ok := findFold(names, msg.Commit.Type); ok {
+		// Reported by type/case
+		return nil
+	}
+
+	return []Diagnostic{{
+		Range: msg.Header.TypeRange,
+		Type:  UnknownTypeWarning,
+		Args:  []string{msg.Commit.Type},
+		Fixes: suggestionFixes(msg.Header.TypeRange, Suggestions(strings.TrimSpace(msg.Commit.Type), names)),
+	}}
+}
+
+// The scopes that may be used, or an empty list if any scope may be used
+func allowedScopes(ctx *Context) []string {
+	cfg := ctx.config()
+	scopes := slices.Clone(cfg.Scopes)
+	if !cfg.ScopesFromHistory {
+		return scopes
+	}
+
+	for _, scope := range HistoryScopes(ctx.Repo, cfg) {
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// The scopes used in the history of the branch, newest first, without the deprecated scopes
+func HistoryScopes(repo Repo, cfg *config.Config) []string {
+	scopes := []string{}
+	for _, entry := range branchLog(repo) {
+		msg := ParseMessage(entry.Subject)
+		msg.SplitScopes(cfg.ScopeDelimiters)
+		for _, scope := range msg.Commit.Scopes {
+			if !slices.Contains(scopes, scope) && !slices.Contains(cfg.DeprecatedScopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// Whether the scopes of the message should be allowed scopes
+func hasCheckedScopes(msg *Message) bool {
+	return msg.Header.HasTypeScope && msg.Header.LParen != -1 && msg.Commit.Kind == NormalKind
+}
+
+func checkScopeCase(ctx *Context) []Diagnostic {
+	msg := ctx.Message
+	if !hasCheckedScopes(msg) || len(msg.Commit.Scopes) == 0 {
+		return nil
+	}
+
+	scopes := allowedScopes(ctx)
+	diagnostics := []Diagnostic{}
+	for i, scope := range msg.Commit.Scopes {
+		allowed, ok := findFold(scopes, scope)
+		if !ok || slices.Contains(scopes, scope) {
+			continue
+		}
+
+		scopeRange := msg.Header.ScopeRanges[i]
+		diagnostics = append(diagnostics, Diagnostic{
+			Range: scopeRange,
+			Type:  ScopeCaseWarning,
+			Args:  []string{scope, allowed},
+			Fixes: suggestionFixes(scopeRange, []string{allowed}),
+		})
+	}
+
+	return diagnostics
+}
+
+func checkUnknownScope(ctx *Context) []Diagnostic {
+	msg := ctx.Message
+	if !hasCheckedScopes(msg) || len(msg.Commit.Scopes) == 0 {
+		return nil
+	}
+
+	scopes := allowedScopes(ctx)
+	if len(scopes) == 0 {
+		return nil
+	}
+
+	diagnostics := []Diagnostic{}
+	for i, scope := range msg.Commit.Scopes {
+		if _, ok := findFold(scopes, scope); ok {
+			continue
+		}
+
+		scopeRange := msg.Header.ScopeRanges[i]
+		diagnostics = append(diagnostics, Diagnostic{
+			Range: scopeRange,
+			Type:  UnknownScopeWarning,
+			Args:  []string{scope},
+			Fixes: suggestionFixes(scopeRange, Suggestions(scope, scopes)),
+		})
+	}
+
+	return diagnostics
+}
+
+// Whitespace is reported inside each scope, and before the first and after the last scope.
+// Whitespace after a delimiter is allowed (e.g. "api, ui")
+func checkScopeWhitespace(ctx *Context) []Diagnostic {
+	msg := ctx.Message
+	header := msg.Header
+	if !hasCheckedScopes(msg) || len(msg.Commit.Scopes) == 0 {
+		return nil
+	}
+
+	diagnostics := []Diagnostic{}
+	removeWhitespace := func(start int, end int) {
+		if start == end {
+			return
+		}
+		spaceRange := helper.LineRange(0, start, end)
+		diagnostics = append(diagnostics, Diagnostic{
+			Range: spaceRange,
+			Type:  ScopeWhitespaceWarning,
+			Fixes: []Fix{{
+				Title: "Remove whitespace",
+				Edits: []lsp.TextEdit{{Range: spaceRange, NewText: ""}},
+			}},
+		})
+	}
+
+	removeWhitespace(header.ScopeRange.Start.Character, header.ScopeRanges[0].Start.Character)
+	for i, scope := range msg.Commit.Scopes {
+		if !strings.ContainsAny(scope, " \t") {
+			continue
+		}
+
+		// Words in the scope are joined with "-" (e.g. "ui kit" -> "ui-kit")
+		fixed := strings.Join(strings.Fields(scope), "-")
+		scopeRange := header.ScopeRanges[i]
+		diagnostics = append(diagnostics, Diagnostic{
+			Range: scopeRange,
+			Type:  ScopeWhitespaceWarning,
+			Fixes: []Fix{{
+				Title: fmt.Sprintf("Change to '%s'", fixed),
+				Edits: []lsp.TextEdit{{Range: scopeRange, NewText: fixed}},
+			}},
+		})
+	}
+	removeWhitespace(header.ScopeRanges[len(header.ScopeRanges)-1].End.Character, header.ScopeRange.End.Character)
+
+	return diagnostics
+}
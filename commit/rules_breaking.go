@@ -0,0 +1,193 @@
+package commit
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// Diagnostic error/warning types
+const (
+	// The commit is marked as breaking, but the breaking change has no description (e.g. an empty
+	// BREAKING CHANGE footer)
+	EmptyBreakingChangeError DiagnosticType = "breaking/empty-description"
+	// There is a BREAKING CHANGE footer, but no "!" in the header (unless breakingChange is "any")
+	MissingBreakingBangWarning DiagnosticType = "breaking/missing-bang"
+	// There is a "!" in the header, but no BREAKING CHANGE footer (only if breakingChange is "both")
+	MissingBreakingFooterWarning DiagnosticType = "breaking/missing-footer"
+	// A line looks like a BREAKING CHANGE footer, but is not written exactly like one, so it is
+	// ignored (e.g. "breaking change: x", "BREAKING CHANGES: x")
+	// Args: 0 = the misspelled key and separator
+	MisspelledBreakingFooterWarning DiagnosticType = "breaking/misspelled-footer"
+)
+
+const BreakingChangeKey = "BREAKING CHANGE"
+
+// Rules for the consistency of the "!" in the header and the BREAKING CHANGE footer
+
+func init() {
+	Register(NewRule(RuleInfo{
+		ID:          EmptyBreakingChangeError,
+		Description: "A breaking change must be described, in the BREAKING CHANGE footer or the description",
+		Severity:    lsp.DiagnosticSeverityError,
+		DocURL:      conventionalCommitsSpecURL,
+		Message:     staticMessage("Breaking change has no description"),
+	}, checkEmptyBreakingChange))
+	Register(NewRule(RuleInfo{
+		ID:          MissingBreakingBangWarning,
+		Description: "A commit with a BREAKING CHANGE footer must have a \"!\" before the colon in the header (unless breakingChange is \"any\")",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		Message:     staticMessage("Breaking change is not marked with '!' in the header"),
+	}, checkMissingBreakingBang))
+	Register(NewRule(RuleInfo{
+		ID:          MissingBreakingFooterWarning,
+		Description: "A commit with a \"!\" in the header must have a BREAKING CHANGE footer (if breakingChange is \"both\")",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		Message:     staticMessage("Breaking change has no BREAKING CHANGE footer"),
+	}, checkMissingBreakingFooter))
+	Register(NewRule(RuleInfo{
+		ID:          MisspelledBreakingFooterWarning,
+		Description: "BREAKING CHANGE footers must be written in upper case, with a space or '-' between the words",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		DocURL:      conventionalCommitsSpecURL,
+		Message:     misspelledBreakingFooterMessage,
+	}, checkMisspelledBreakingFooter))
+}
+
+// The BREAKING CHANGE (or BREAKING-CHANGE) footer, or nil if there is none
+func (self *Message) breakingFooter() *Footer {
+	for i, footer := range self.Footers {
+		if footer.Key == BreakingChangeKey || footer.Key == "BREAKING-CHANGE" {
+			return &self.Footers[i]
+		}
+	}
+	return nil
+}
+
+// Edit that adds a footer after the last footer, or in a new paragraph if there are none
+func (self *Message) addFooterEdit(footer string) lsp.TextEdit {
+	if len(self.Footers) > 0 {
+		end := self.Footers[len(self.Footers)-1].Range.End
+		return lsp.TextEdit{Range: lsp.Range{Start: end, End: end}, NewText: "\n" + footer}
+	}
+
+	last := self.lastContentLine()
+	return lsp.TextEdit{
+		Range:   helper.LineRange(last, len(self.Lines[last]), len(self.Lines[last])),
+		NewText: "\n\n" + footer,
+	}
+}
+
+// Whether the header can have a "!" (i.e. it has a type)
+func hasBreakingMarker(msg *Message) bool {
+	return msg.Header.HasTypeScope && msg.Commit.Kind == NormalKind
+}
+
+func checkEmptyBreakingChange(ctx *Context) []Diagnostic {
+	msg := ctx.Message
+	header := msg.Header
+
+	if footer := msg.breakingFooter(); footer != nil {
+		if footer.Value != "" {
+			return nil
+		}
+		return []Diagnostic{{
+			Range: footer.Range,
+			Type:  EmptyBreakingChangeError,
+		}}
+	}
+
+	if !hasBreakingMarker(msg) || header.Bang == -1 || msg.Commit.BreakingChange != "" {
+		return nil
+	}
+	return []Diagnostic{{
+		Range: helper.LineRange(0, header.Bang, header.Bang+1),
+		Type:  EmptyBreakingChangeError,
+		Fixes: []Fix{{
+			Title: "Add BREAKING CHANGE footer",
+			Edits: []lsp.TextEdit{msg.addFooterEdit(BreakingChangeKey + ": ")},
+		}},
+	}}
+}
+
+func checkMissingBreakingBang(ctx *Context) []Diagnostic {
+	msg := ctx.Message
+	header := msg.Header
+	footer := msg.breakingFooter()
+	if ctx.config().BreakingChange == config.BreakingChangeAny || footer == nil || !hasBreakingMarker(msg) || header.Bang != -1 {
+		return nil
+	}
+
+	return []Diagnostic{{
+		Range: footer.KeyRange,
+		Type:  MissingBreakingBangWarning,
+		Fixes: []Fix{{
+			Title: "Add '!' to the header",
+			Edits: []lsp.TextEdit{{Range: helper.LineRange(0, header.PrefixEnd, header.PrefixEnd), NewText: "!"}},
+		}},
+	}}
+}
+
+func checkMissingBreakingFooter(ctx *Context) []Diagnostic {
+	msg := ctx.Message
+	header := msg.Header
+	if ctx.config().BreakingChange != config.BreakingChangeBoth || !hasBreakingMarker(msg) || header.Bang == -1 || msg.breakingFooter() != nil {
+		return nil
+	}
+
+	return []Diagnostic{{
+		Range: helper.LineRange(0, header.Bang, header.Bang+1),
+		Type:  MissingBreakingFooterWarning,
+		Fixes: []Fix{{
+			Title: "Add BREAKING CHANGE footer",
+			Edits: []lsp.TextEdit{msg.addFooterEdit(fmt.Sprintf("%s: %s", BreakingChangeKey, msg.Commit.Description))},
+		}},
+	}}
+}
+
+// "breaking change:", "Breaking-Changes :", "BREAKING_CHANGE #", ...
+var breakingFooterPattern = regexp.MustCompile(`(?i)^breaking[ _-]?changes?\s*(?::|\s#)\s*`)
+
+func misspelledBreakingFooterMessage(diagnostic Diagnostic) string {
+	if len(diagnostic.Args) < 1 {
+		return ""
+	}
+	return fmt.Sprintf("'%s' is not a BREAKING CHANGE footer", strings.TrimSpace(diagnostic.Args[0]))
+}
+
+func checkMisspelledBreakingFooter(ctx *Context) []Diagnostic {
+	msg := ctx.Message
+
+	diagnostics := []Diagnostic{}
+	for line := 1; line < msg.End(); line++ {
+		if msg.IsComment(line) {
+			continue
+		}
+
+		text := msg.Lines[line]
+		match := breakingFooterPattern.FindString(text)
+		if match == "" {
+			continue
+		}
+		if key, _, ok := ParseFooter(text); ok && (key == BreakingChangeKey || key == "BREAKING-CHANGE") {
+			continue
+		}
+
+		keyRange := helper.LineRange(line, 0, len(match))
+		diagnostics = append(diagnostics, Diagnostic{
+			Range: keyRange,
+			Type:  MisspelledBreakingFooterWarning,
+			Args:  []string{match},
+			Fixes: []Fix{{
+				Title: "Change to 'BREAKING CHANGE: '",
+				Edits: []lsp.TextEdit{{Range: keyRange, NewText: BreakingChangeKey + ": "}},
+			}},
+		})
+	}
+
+	return diagnostics
+}
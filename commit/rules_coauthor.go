@@ -0,0 +1,180 @@
+package commit
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/eamonburns/git-lsp/git"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// Diagnostic error/warning types
+const (
+	// The email of a co-author does not appear in the history of the repository
+	// Args: 0 = email
+	UnknownCoAuthorWarning DiagnosticType = "trailer/unknown-co-author"
+	// The GitHub noreply email of a co-author is different from the one in the history
+	// Args: 0 = email, 1 = email in the history
+	NoreplyMismatchWarning DiagnosticType = "trailer/noreply-mismatch"
+)
+
+const CoAuthorKey = "Co-authored-by"
+
+// How many commits are searched for co-authors
+const maxAuthorLogEntries = 10000
+
+func init() {
+	Register(NewRule(RuleInfo{
+		ID:          UnknownCoAuthorWarning,
+		Description: "Co-authors should have committed to the repository with the same email before",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		Message:     formatMessage("'%s' has not committed to this repository", 1),
+	}, checkUnknownCoAuthors))
+	Register(NewRule(RuleInfo{
+		ID:          NoreplyMismatchWarning,
+		Description: "GitHub noreply emails of co-authors must match the ones in the history, or GitHub does not credit them",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		DocURL:      "https://docs.github.com/en/pull-requests/committing-changes-to-your-project/creating-and-editing-commits/creating-a-commit-with-multiple-authors",
+		Message:     formatMessage("'%s' does not match the noreply email in the history: '%s'", 2),
+	}, checkNoreplyMismatch))
+}
+
+// The authors in the history of a repository, and the identities mapped with its .mailmap
+type authorHistory struct {
+	head    string
+	authors []git.Author
+	mailmap map[git.Identity]git.Identity
+}
+
+var (
+	authorCacheMu sync.Mutex
+	// The history of the current HEAD of each repository, by root. Reading it takes a while in
+	// large repositories, and the rules are checked on every change of the message
+	authorCache = map[string]*authorHistory{}
+)
+
+// The authors of the repository, and the co-authors mapped with .mailmap
+func coAuthorHistory(root string, coAuthors []git.Identity) ([]git.Author, []git.Identity, error) {
+	head, _ := git.RevParse(root, "HEAD")
+
+	authorCacheMu.Lock()
+	defer authorCacheMu.Unlock()
+
+	history, ok := authorCache[root]
+	if !ok || history.head != head {
+		authors, err := git.Authors(root, nil, maxAuthorLogEntries)
+		if err != nil {
+			return nil, nil, err
+		}
+		history = &authorHistory{head: head, authors: authors, mailmap: map[git.Identity]git.Identity{}}
+		authorCache[root] = history
+	}
+
+	mapped := []git.Identity{}
+	for _, identity := range coAuthors {
+		if _, ok := history.mailmap[identity]; !ok {
+			history.mailmap[identity] = git.CheckMailmap(root, identity)
+		}
+		mapped = append(mapped, history.mailmap[identity])
+	}
+	return history.authors, mapped, nil
+}
+
+// "12345+user@users.noreply.github.com" or "user@users.noreply.github.com"
+var noreplyPattern = regexp.MustCompile(`(?i)^(?:[0-9]+\+)?([^@+]+)@users\.noreply\.github\.com$`)
+
+// The GitHub user of a noreply email, or "" if it is not one
+func noreplyUser(email string) string {
+	if match := noreplyPattern.FindStringSubmatch(email); match != nil {
+		return strings.ToLower(match[1])
+	}
+	return ""
+}
+
+func checkUnknownCoAuthors(ctx *Context) []Diagnostic {
+	return coAuthorDiagnostics(ctx, UnknownCoAuthorWarning)
+}
+
+func checkNoreplyMismatch(ctx *Context) []Diagnostic {
+	return coAuthorDiagnostics(ctx, NoreplyMismatchWarning)
+}
+
+// Both rules compare the co-authors with the same authors, so they are checked together, and each
+// keeps the diagnostics of its own type
+func coAuthorDiagnostics(ctx *Context, diagnosticType DiagnosticType) []Diagnostic {
+	if ctx.Repo.Root == "" {
+		return nil
+	}
+
+	trailers := []Trailer{}
+	identities := []git.Identity{}
+	for _, trailer := range ctx.Message.Trailers(ctx.trailerSeparators()) {
+		if !strings.EqualFold(trailer.Key, CoAuthorKey) {
+			continue
+		}
+		if identity, ok := git.ParseIdentity(trailer.Value); ok {
+			trailers = append(trailers, trailer)
+			identities = append(identities, identity)
+		}
+	}
+	if len(trailers) == 0 {
+		return nil
+	}
+
+	authors, identities, err := coAuthorHistory(ctx.Repo.Root, identities)
+	if err != nil {
+		slog.Debug("unable to read authors", "root", ctx.Repo.Root, "error", err)
+		return nil
+	}
+
+	diagnostics := []Diagnostic{}
+	for i, trailer := range trailers {
+		identity := identities[i]
+
+		var sameName, sameNoreplyUser *git.Author
+		known := false
+		for i, author := range authors {
+			if strings.EqualFold(author.Email, identity.Email) {
+				known = true
+				break
+			}
+			if sameName == nil && strings.EqualFold(author.Name, identity.Name) {
+				sameName = &authors[i]
+			}
+			if user := noreplyUser(identity.Email); sameNoreplyUser == nil && user != "" && user == noreplyUser(author.Email) {
+				sameNoreplyUser = &authors[i]
+			}
+		}
+		if known {
+			continue
+		}
+
+		diagnostic := Diagnostic{
+			Range: trailer.ValueRange,
+			Type:  UnknownCoAuthorWarning,
+			Args:  []string{identity.Email},
+		}
+		replacement := sameName
+		if sameNoreplyUser != nil {
+			diagnostic.Type = NoreplyMismatchWarning
+			diagnostic.Args = append(diagnostic.Args, sameNoreplyUser.Email)
+			replacement = sameNoreplyUser
+		}
+		if diagnostic.Type != diagnosticType {
+			continue
+		}
+		if replacement != nil {
+			diagnostic.Fixes = []Fix{{
+				Title: fmt.Sprintf("Replace with %s", replacement.Identity),
+				Edits: []lsp.TextEdit{{Range: trailer.ValueRange, NewText: replacement.Identity.String()}},
+			}}
+		}
+
+		diagnostics = append(diagnostics, diagnostic)
+	}
+
+	return diagnostics
+}
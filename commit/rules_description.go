@@ -0,0 +1,221 @@
+package commit
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// Diagnostic error/warning types
+const (
+	// The first letter of the description is not in the configured case (description.case)
+	// Args: 0 = case ("lower" or "sentence")
+	DescriptionCaseWarning DiagnosticType = "description/case"
+	// The description ends with a period (e.g. "feat: add x.")
+	DescriptionTrailingPeriodWarning DiagnosticType = "description/trailing-period"
+	// The description does not start with a verb in the imperative mood (e.g. "feat: added x")
+	// Args: 0 = word, 1 = imperative form
+	DescriptionNotImperativeWarning DiagnosticType = "description/imperative"
+	// There is more than one space before the description, or whitespace after it
+	DescriptionWhitespaceWarning DiagnosticType = "description/whitespace"
+)
+
+// Rules for the style of the description in the header
+
+func init() {
+	Register(NewRule(RuleInfo{
+		ID:          DescriptionCaseWarning,
+		Description: "The description must start with a lower case letter (or an upper case letter if description.case is \"sentence\")",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		Message:     descriptionCaseMessage,
+	}, checkDescriptionCase))
+	Register(NewRule(RuleInfo{
+		ID:          DescriptionTrailingPeriodWarning,
+		Description: "The description must not end with a period",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		Message:     staticMessage("Description ends with a period"),
+	}, checkDescriptionTrailingPeriod))
+	Register(NewRule(RuleInfo{
+		ID:          DescriptionNotImperativeWarning,
+		Description: "The description must start with a verb in the imperative mood (\"add\", not \"added\" or \"adds\")",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		DocURL:      "https://cbea.ms/git-commit/#imperative",
+		Message:     formatMessage("Use the imperative mood: '%[2]s' instead of '%[1]s'", 2),
+	}, checkDescriptionImperative))
+	Register(NewRule(RuleInfo{
+		ID:          DescriptionWhitespaceWarning,
+		Description: "The description must be separated from the colon by a single space, and must not end with whitespace",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		Message:     staticMessage("Extra whitespace around description"),
+	}, checkDescriptionWhitespace))
+}
+
+// Whether the description was written by a person, so that its style can be checked
+func hasStyledDescription(msg *Message) bool {
+	return msg.Commit.Kind == NormalKind && msg.Commit.Description != ""
+}
+
+// The first word of the description, and its range. Returns false if it is not made of letters
+// only (e.g. a file name or an identifier), so its case is probably significant
+func (self *Message) firstWord() (string, lsp.Range, bool) {
+	description := self.Commit.Description
+	word, _, _ := strings.Cut(description, " ")
+	word = strings.TrimRight(word, ",:;")
+
+	for _, r := range word {
+		if !unicode.IsLetter(r) {
+			return "", lsp.Range{}, false
+		}
+	}
+
+	start := self.Header.DescriptionRange.Start.Character
+	return word, helper.LineRange(0, start, start+len(word)), word != ""
+}
+
+// Upper case the first letter of the word
+func capitalize(word string) string {
+	r, size := utf8.DecodeRuneInString(word)
+	return string(unicode.ToUpper(r)) + word[size:]
+}
+
+func descriptionCaseMessage(diagnostic Diagnostic) string {
+	if len(diagnostic.Args) > 0 && diagnostic.Args[0] == config.CaseSentence {
+		return "Description must start with an upper case letter"
+	}
+	return "Description must start with a lower case letter"
+}
+
+func checkDescriptionCase(ctx *Context) []Diagnostic {
+	msg := ctx.Message
+	descriptionCase := ctx.config().Description.Case
+	if descriptionCase == config.CaseAny || !hasStyledDescription(msg) {
+		return nil
+	}
+
+	word, wordRange, ok := msg.firstWord()
+	if !ok {
+		return nil
+	}
+	first, size := utf8.DecodeRuneInString(word)
+	rest := word[size:]
+
+	var rewritten string
+	switch descriptionCase {
+	case config.CaseLower:
+		// Words with other upper case letters are names or acronyms (e.g. "GitHub", "API")
+		if !unicode.IsUpper(first) || strings.ToLower(rest) != rest {
+			return nil
+		}
+		rewritten = strings.ToLower(word)
+	case config.CaseSentence:
+		// Words with upper case letters are names (e.g. "iOS")
+		if !unicode.IsLower(first) || strings.ToLower(rest) != rest {
+			return nil
+		}
+		rewritten = capitalize(word)
+	default:
+		return nil
+	}
+
+	return []Diagnostic{{
+		Range: wordRange,
+		Type:  DescriptionCaseWarning,
+		Args:  []string{descriptionCase},
+		Fixes: []Fix{{
+			Title: fmt.Sprintf("Change to '%s'", rewritten),
+			Edits: []lsp.TextEdit{{Range: wordRange, NewText: rewritten}},
+		}},
+	}}
+}
+
+func checkDescriptionTrailingPeriod(ctx *Context) []Diagnostic {
+	msg := ctx.Message
+	description := msg.Commit.Description
+	// An ellipsis is allowed
+	if !hasStyledDescription(msg) || !strings.HasSuffix(description, ".") || strings.HasSuffix(description, "..") {
+		return nil
+	}
+
+	end := msg.Header.DescriptionRange.End.Character
+	periodRange := helper.LineRange(0, end-1, end)
+	return []Diagnostic{{
+		Range: periodRange,
+		Type:  DescriptionTrailingPeriodWarning,
+		Fixes: []Fix{{
+			Title: "Remove trailing period",
+			Edits: []lsp.TextEdit{{Range: periodRange, NewText: ""}},
+		}},
+	}}
+}
+
+func checkDescriptionImperative(ctx *Context) []Diagnostic {
+	msg := ctx.Message
+	if !hasStyledDescription(msg) {
+		return nil
+	}
+
+	word, wordRange, ok := msg.firstWord()
+	if !ok {
+		return nil
+	}
+	imperative, ok := imperativeOf(word)
+	if !ok {
+		return nil
+	}
+	if first, _ := utf8.DecodeRuneInString(word); unicode.IsUpper(first) {
+		imperative = capitalize(imperative)
+	}
+
+	return []Diagnostic{{
+		Range: wordRange,
+		Type:  DescriptionNotImperativeWarning,
+		Args:  []string{word, imperative},
+		Fixes: []Fix{{
+			Title: fmt.Sprintf("Change to '%s'", imperative),
+			Edits: []lsp.TextEdit{{Range: wordRange, NewText: imperative}},
+		}},
+	}}
+}
+
+func checkDescriptionWhitespace(ctx *Context) []Diagnostic {
+	msg := ctx.Message
+	header := msg.Header
+	if !hasStyledDescription(msg) {
+		return nil
+	}
+
+	diagnostics := []Diagnostic{}
+
+	// No space at all is reported by header/no-space-before-description
+	start := header.DescriptionRange.Start.Character
+	if header.HasTypeScope && start > header.Colon+2 {
+		spaceRange := helper.LineRange(0, header.Colon+1, start)
+		diagnostics = append(diagnostics, Diagnostic{
+			Range: spaceRange,
+			Type:  DescriptionWhitespaceWarning,
+			Fixes: []Fix{{
+				Title: "Replace with a single space",
+				Edits: []lsp.TextEdit{{Range: spaceRange, NewText: " "}},
+			}},
+		})
+	}
+
+	if trimmed := strings.TrimRight(header.Text, " \t"); len(trimmed) < len(header.Text) {
+		spaceRange := helper.LineRange(0, len(trimmed), len(header.Text))
+		diagnostics = append(diagnostics, Diagnostic{
+			Range: spaceRange,
+			Type:  DescriptionWhitespaceWarning,
+			Fixes: []Fix{{
+				Title: "Remove trailing whitespace",
+				Edits: []lsp.TextEdit{{Range: spaceRange, NewText: ""}},
+			}},
+		})
+	}
+
+	return diagnostics
+}
@@ -0,0 +1,151 @@
+package commit
+
+import (
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// Rules that compare the message with the diff below the scissors line. They only apply to
+// messages written with `git commit --verbose`
+
+// Diagnostic error/warning types
+const (
+	// The message mentions a file that is not in the diff
+	// Args: 0 = file
+	FileNotInDiffWarning DiagnosticType = "diff/file-not-changed"
+	// The message mentions a function that is not in the diff
+	// Args: 0 = function
+	FunctionNotInDiffWarning DiagnosticType = "diff/function-not-changed"
+)
+
+func init() {
+	Register(NewRule(RuleInfo{
+		ID:          FileNotInDiffWarning,
+		Description: "Files mentioned in the message (e.g. \"lua/init.lua\" or \"`state.go`\") should be changed by the commit",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		Message:     formatMessage("'%s' is not changed by this commit", 1),
+	}, checkFilesInDiff))
+	Register(NewRule(RuleInfo{
+		ID:          FunctionNotInDiffWarning,
+		Description: "Functions mentioned in the message (e.g. \"parse()\") should be changed by the commit",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		Message:     formatMessage("'%s' is not in the diff of this commit", 1),
+	}, checkFunctionsInDiff))
+}
+
+// A word with a file extension, optionally with directories (e.g. "state.go", "lua/min_init.lua")
+var filePattern = regexp.MustCompile(`(?:^|[\s(\x60'"])((?:[\w.-]+/)*[\w-]+\.([A-Za-z][A-Za-z0-9]*))\b`)
+
+// Extensions of files without a directory in backticks, so that "`config.Default`" is not a
+// file
+var fileExtensions = []string{
+	"c", "cc", "cpp", "cs", "css", "go", "h", "hpp", "html", "java", "js", "json", "jsx", "kt", "lua",
+	"md", "mod", "php", "proto", "py", "rb", "rs", "scss", "sh", "sql", "sum", "swift", "toml", "ts",
+	"tsx", "txt", "vim", "xml", "yaml", "yml", "zig",
+}
+
+// An identifier followed by "()" (e.g. "parse()", "State.Hover()")
+var functionPattern = regexp.MustCompile(`(?:^|[^\w.])([A-Za-z_][\w.]*)\(\)`)
+
+// Lines of the description and body, which is where files and functions are mentioned
+func (self *Message) proseLines() []int {
+	lines := []int{0}
+	for _, paragraph := range self.Body {
+		for line := paragraph.Start.Line; line <= paragraph.End.Line; line++ {
+			if !self.IsComment(line) {
+				lines = append(lines, line)
+			}
+		}
+	}
+
+	return lines
+}
+
+// Whether the range is inside a URL, where "file names" are most likely domains or pages
+func insideURL(references []Reference, r lsp.Range) bool {
+	return slices.ContainsFunc(references, func(reference Reference) bool {
+		return reference.Kind == URLReference && helper.RangesOverlap(reference.Range, r)
+	})
+}
+
+func checkFilesInDiff(ctx *Context) []Diagnostic {
+	msg := ctx.Message
+	if len(msg.Diff) == 0 {
+		return nil
+	}
+
+	diagnostics := []Diagnostic{}
+	references := msg.References(ctx.config())
+	for _, line := range msg.proseLines() {
+		text := msg.Lines[line]
+		if line == 0 {
+			text = text[:msg.Header.DescriptionRange.End.Character]
+		}
+
+		for _, match := range filePattern.FindAllStringSubmatchIndex(text, -1) {
+			// Words like "Node.js" or "e.g." look like files too, so files without a directory
+			// are only recognized in backticks
+			file, extension := text[match[2]:match[3]], text[match[4]:match[5]]
+			inBackticks := match[2] > 0 && text[match[2]-1] == '`' && match[3] < len(text) && text[match[3]] == '`'
+			if !strings.Contains(file, "/") && !(inBackticks && slices.Contains(fileExtensions, strings.ToLower(extension))) {
+				continue
+			}
+
+			fileRange := helper.LineRange(line, match[2], match[3])
+			if (line == 0 && match[2] < msg.Header.DescriptionRange.Start.Character) || insideURL(references, fileRange) {
+				continue
+			}
+
+			if !msg.DiffHasFile(path.Clean(file)) {
+				diagnostics = append(diagnostics, Diagnostic{
+					Range: fileRange,
+					Type:  FileNotInDiffWarning,
+					Args:  []string{file},
+				})
+			}
+		}
+	}
+
+	return diagnostics
+}
+
+func checkFunctionsInDiff(ctx *Context) []Diagnostic {
+	msg := ctx.Message
+	if len(msg.Diff) == 0 {
+		return nil
+	}
+
+	diagnostics := []Diagnostic{}
+	for _, line := range msg.proseLines() {
+		text := msg.Lines[line]
+		if line == 0 {
+			text = text[:msg.Header.DescriptionRange.End.Character]
+		}
+
+		for _, match := range functionPattern.FindAllStringSubmatchIndex(text, -1) {
+			if line == 0 && match[2] < msg.Header.DescriptionRange.Start.Character {
+				continue
+			}
+
+			function := text[match[2]:match[3]]
+			// Only the last part of "State.Hover" has to be in the diff
+			name := function[strings.LastIndex(function, ".")+1:]
+			if name == "" || msg.DiffContains(name) {
+				continue
+			}
+
+			diagnostics = append(diagnostics, Diagnostic{
+				Range: helper.LineRange(line, match[2], match[3]+2),
+				Type:  FunctionNotInDiffWarning,
+				Args:  []string{function},
+			})
+		}
+	}
+
+	return diagnostics
+}
@@ -0,0 +1,220 @@
+package commit
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+const conventionalCommitsSpecURL = "https://www.conventionalcommits.org/en/v1.0.0/#specification"
+
+// Rules for the syntax of the "type(scope)!: description" header line
+
+func init() {
+	Register(NewRule(RuleInfo{
+		ID:          NoTypeScopeError,
+		Description: "The header must start with a type (and optional scope), followed by a colon",
+		Severity:    lsp.DiagnosticSeverityError,
+		DocURL:      conventionalCommitsSpecURL,
+		Message:     staticMessage("No type/scope in header line"),
+	}, checkNoTypeScope))
+	Register(NewRule(RuleInfo{
+		ID:          UnmatchedLeftParenError,
+		Description: "The '(' before the scope must have a matching ')'",
+		Severity:    lsp.DiagnosticSeverityError,
+		DocURL:      conventionalCommitsSpecURL,
+		Message:     staticMessage("Unmatched '('"),
+	}, checkUnmatchedLeftParen))
+	Register(NewRule(RuleInfo{
+		ID:          UnmatchedRightParenError,
+		Description: "The ')' after the scope must have a matching '('",
+		Severity:    lsp.DiagnosticSeverityError,
+		DocURL:      conventionalCommitsSpecURL,
+		Message:     staticMessage("Unmatched ')'"),
+	}, checkUnmatchedRightParen))
+	Register(NewRule(RuleInfo{
+		ID:          ExtraCharactersAfterScopeError,
+		Description: "The scope must be followed by an optional '!' and a colon",
+		Severity:    lsp.DiagnosticSeverityError,
+		DocURL:      conventionalCommitsSpecURL,
+		Message:     formatMessage("Extra characters after scope: '%s'", 1),
+	}, checkExtraCharactersAfterScope))
+	Register(NewRule(RuleInfo{
+		ID:          EmptyTypeError,
+		Description: "The type must not be empty",
+		Severity:    lsp.DiagnosticSeverityError,
+		DocURL:      conventionalCommitsSpecURL,
+		Message:     staticMessage("Empty type"),
+	}, checkEmptyType))
+	Register(NewRule(RuleInfo{
+		ID:          EmptyScopeError,
+		Description: "If there are parentheses after the type, the scope inside them must not be empty",
+		Severity:    lsp.DiagnosticSeverityError,
+		DocURL:      conventionalCommitsSpecURL,
+		Message:     staticMessage("Empty scope"),
+	}, checkEmptyScope))
+	Register(NewRule(RuleInfo{
+		ID:          EmptyDescriptionError,
+		Description: "The description after the colon must not be empty",
+		Severity:    lsp.DiagnosticSeverityError,
+		DocURL:      conventionalCommitsSpecURL,
+		Message:     staticMessage("Empty description"),
+	}, checkEmptyDescription))
+	Register(NewRule(RuleInfo{
+		ID:          NoSpaceBeforeDescriptionError,
+		Description: "The colon after the type/scope must be followed by a space",
+		Severity:    lsp.DiagnosticSeverityError,
+		DocURL:      conventionalCommitsSpecURL,
+		Message:     staticMessage("No space before description"),
+	}, checkNoSpaceBeforeDescription))
+}
+
+func checkNoTypeScope(ctx *Context) []Diagnostic {
+	header := ctx.Message.Header
+	if header.HasTypeScope || ctx.Message.Commit.Kind != NormalKind {
+		// Messages generated by git don't have a type/scope
+		return nil
+	}
+
+	return []Diagnostic{{
+		Range: helper.LineRange(0, 0, len(header.Text)),
+		Type:  NoTypeScopeError,
+	}}
+}
+
+func checkUnmatchedLeftParen(ctx *Context) []Diagnostic {
+	header := ctx.Message.Header
+	if !header.HasTypeScope || header.LParen == -1 || header.RParen != -1 {
+		return nil
+	}
+
+	return []Diagnostic{{
+		Range: helper.LineRange(0, header.LParen, header.LParen),
+		Type:  UnmatchedLeftParenError,
+	}}
+}
+
+func checkUnmatchedRightParen(ctx *Context) []Diagnostic {
+	header := ctx.Message.Header
+	if !header.HasTypeScope || header.LParen != -1 || header.RParen == -1 {
+		return nil
+	}
+
+	return []Diagnostic{{
+		Range: helper.LineRange(0, header.RParen, header.RParen),
+		Type:  UnmatchedRightParenError,
+	}}
+}
+
+func checkExtraCharactersAfterScope(ctx *Context) []Diagnostic {
+	header := ctx.Message.Header
+	if !header.HasTypeScope || header.LParen == -1 || header.RParen == -1 || header.RParen == header.PrefixEnd-1 {
+		// The right parentheses is the last character of the type/scope
+		return nil
+	}
+
+	extra := header.Text[header.RParen+1 : header.PrefixEnd]
+	extraRange := helper.LineRange(0, header.RParen+1, header.PrefixEnd)
+	diagnostic := Diagnostic{
+		Range: extraRange,
+		Type:  ExtraCharactersAfterScopeError,
+		Args:  []string{extra},
+		Fixes: []Fix{{
+			Title: "Remove extra characters",
+			Edits: []lsp.TextEdit{{Range: extraRange, NewText: ""}},
+		}},
+	}
+
+	// More scopes in their own parentheses (e.g. "feat(api)(ui)") are moved into the first ones
+	if match := extraScopesPattern.FindStringSubmatch(extra); match != nil {
+		delimiter := firstScopeDelimiter(ctx.config())
+		scopes := strings.Split(match[1], ")(")
+		diagnostic.Fixes = append(diagnostic.Fixes, Fix{
+			Title: "Merge scopes",
+			Edits: []lsp.TextEdit{{
+				Range:   helper.LineRange(0, header.RParen, header.PrefixEnd),
+				NewText: delimiter + strings.Join(scopes, delimiter) + ")",
+			}},
+		})
+	}
+
+	return []Diagnostic{diagnostic}
+}
+
+// The first configured scope delimiter, which may be any character
+func firstScopeDelimiter(cfg *config.Config) string {
+	delimiters := cfg.ScopeDelimiters
+	if delimiters == "" {
+		delimiters = config.DefaultScopeDelimiters
+	}
+
+	r, _ := utf8.DecodeRuneInString(delimiters)
+	return string(r)
+}
+
+var extraScopesPattern = regexp.MustCompile(`^\(([^()]+(?:\)\([^()]+)*)\)$`)
+
+func checkEmptyType(ctx *Context) []Diagnostic {
+	if !ctx.Message.Header.HasTypeScope || ctx.Message.Commit.Type != "" {
+		return nil
+	}
+
+	return []Diagnostic{{
+		Range: helper.LineRange(0, 0, 0),
+		Type:  EmptyTypeError,
+	}}
+}
+
+func checkEmptyScope(ctx *Context) []Diagnostic {
+	header := ctx.Message.Header
+	commit := ctx.Message.Commit
+	if !header.HasTypeScope || header.LParen == -1 || commit.Scope != "" {
+		return nil
+	}
+
+	diagnostic := Diagnostic{
+		Range: helper.LineRange(0, len(commit.Type), len(commit.Type)+2),
+		Type:  EmptyScopeError,
+	}
+	if header.RParen == header.LParen+1 {
+		diagnostic.Fixes = []Fix{{
+			Title: "Remove empty scope",
+			Edits: []lsp.TextEdit{{Range: diagnostic.Range, NewText: ""}},
+		}}
+	}
+
+	return []Diagnostic{diagnostic}
+}
+
+func checkEmptyDescription(ctx *Context) []Diagnostic {
+	header := ctx.Message.Header
+	if !header.HasTypeScope || ctx.Message.Commit.Description != "" {
+		return nil
+	}
+
+	return []Diagnostic{{
+		Range: helper.LineRange(0, header.Colon+1, len(header.Text)),
+		Type:  EmptyDescriptionError,
+	}}
+}
+
+func checkNoSpaceBeforeDescription(ctx *Context) []Diagnostic {
+	header := ctx.Message.Header
+	if !header.HasTypeScope || ctx.Message.Commit.Description == "" || header.Text[header.Colon+1] == ' ' {
+		return nil
+	}
+
+	insertRange := helper.LineRange(0, header.Colon+1, header.Colon+1)
+	return []Diagnostic{{
+		Range: insertRange,
+		Type:  NoSpaceBeforeDescriptionError,
+		Fixes: []Fix{{
+			Title: "Insert space before description",
+			Edits: []lsp.TextEdit{{Range: insertRange, NewText: " "}},
+		}},
+	}}
+}
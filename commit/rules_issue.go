@@ -0,0 +1,100 @@
+package commit
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/git"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// Diagnostic error/warning types
+const (
+	// There is no issue reference in the scope, description or an issue footer (e.g. "Refs" or
+	// "Closes", only if enabled with issueReference.required)
+	// Args: 0 = ticket in the name of the current branch, or "" if there is none
+	MissingIssueReferenceError DiagnosticType = "reference/missing-issue"
+)
+
+const RefsKey = "Refs"
+
+// Footers that reference issues (e.g. "Refs: PROJ-1" or "Closes #7")
+var issueFooterKeys = []string{RefsKey, "Closes", "Fixes", "Resolves"}
+
+func init() {
+	Register(NewRule(RuleInfo{
+		ID:          MissingIssueReferenceError,
+		Description: "The message must reference an issue in the scope, the description or a Refs, Closes, Fixes or Resolves footer (if issueReference.required is enabled)",
+		Severity:    lsp.DiagnosticSeverityError,
+		Message:     missingIssueReferenceMessage,
+	}, checkIssueReference))
+}
+
+// The first issue reference in the name of the current branch (e.g. "PROJ-123" for
+// "feature/PROJ-123-foo"), or "" if there is none
+func BranchTicket(repo Repo, cfg *config.Config) string {
+	if repo.Root == "" {
+		return ""
+	}
+
+	branch, ok := git.Branch(repo.Root)
+	if !ok {
+		return ""
+	}
+	references := cfg.FindIssueReferences(branch)
+	if len(references) == 0 {
+		return ""
+	}
+	return branch[references[0][0]:references[0][1]]
+}
+
+func missingIssueReferenceMessage(diagnostic Diagnostic) string {
+	if len(diagnostic.Args) > 0 && diagnostic.Args[0] != "" {
+		return fmt.Sprintf("Missing issue reference (%s from the branch name)", diagnostic.Args[0])
+	}
+	return "Missing issue reference"
+}
+
+func checkIssueReference(ctx *Context) []Diagnostic {
+	cfg := ctx.config()
+	if !cfg.IssueReference.Required {
+		return nil
+	}
+
+	msg := ctx.Message
+	if msg.Commit.Kind != NormalKind && msg.Commit.Kind != RevertKind {
+		// Merges and autosquash commits are not kept in the history as they are
+		return nil
+	}
+
+	if cfg.HasIssueReference(msg.Commit.Scope) || cfg.HasIssueReference(msg.Commit.Description) {
+		return nil
+	}
+	for _, footer := range msg.Footers {
+		isIssueFooter := slices.ContainsFunc(issueFooterKeys, func(key string) bool {
+			return strings.EqualFold(footer.Key, key)
+		})
+		if isIssueFooter && cfg.HasIssueReference(footer.RawValue()) {
+			return nil
+		}
+	}
+
+	ticket := BranchTicket(ctx.Repo, cfg)
+	diagnostic := Diagnostic{
+		Range: helper.LineRange(0, 0, len(msg.Header.Text)),
+		Type:  MissingIssueReferenceError,
+		Args:  []string{ticket},
+	}
+	if ticket != "" {
+		trailer := fmt.Sprintf("%s: %s", RefsKey, ticket)
+		diagnostic.Fixes = []Fix{{
+			Title: fmt.Sprintf("Add '%s'", trailer),
+			Edits: []lsp.TextEdit{msg.addTrailerEdit(msg.Trailers(ctx.trailerSeparators()), trailer)},
+		}}
+	}
+
+	return []Diagnostic{diagnostic}
+}
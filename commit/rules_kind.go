@@ -0,0 +1,120 @@
+package commit
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/git"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// Rules for commits with a message generated by git (see Kind)
+
+// Diagnostic error/warning types
+const (
+	// A revert commit does not say which commit it reverts
+	// Args: 0 = subject of the reverted commit
+	RevertWithoutCommitWarning DiagnosticType = "revert/missing-commit"
+	// The target of a fixup!/squash!/amend! commit does not match a commit on the branch
+	// Args: 0 = target
+	UnknownAutosquashTargetWarning DiagnosticType = "autosquash/unknown-target"
+)
+
+// How many commits of the branch are searched for targets
+const maxLogEntries = 1000
+
+func init() {
+	Register(NewRule(RuleInfo{
+		ID:          RevertWithoutCommitWarning,
+		Description: "Revert commits must include \"This reverts commit <sha>.\"",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		DocURL:      "https://www.conventionalcommits.org/en/v1.0.0/#how-does-conventional-commits-handle-revert-commits",
+		Message:     formatMessage("Revert of '%s' does not say which commit it reverts (\"This reverts commit <sha>.\")", 1),
+	}, checkRevertWithoutCommit))
+	Register(NewRule(RuleInfo{
+		ID:          UnknownAutosquashTargetWarning,
+		Description: "The target of fixup!, squash! and amend! commits must match the subject of a commit on the branch",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		DocURL:      "https://git-scm.com/docs/git-rebase#Documentation/git-rebase.txt---autosquash",
+		Message:     formatMessage("No commit on this branch matches '%s'", 1),
+	}, checkUnknownAutosquashTarget))
+}
+
+// The commits of the branch, or nil if they can't be read (e.g. the repository is unknown or has
+// no commits yet)
+func branchLog(repo Repo) []git.LogEntry {
+	if repo.Root == "" {
+		return nil
+	}
+
+	entries, err := git.Log(repo.Root, maxLogEntries)
+	if err != nil {
+		slog.Debug("unable to read log", "root", repo.Root, "error", err)
+		return nil
+	}
+	return entries
+}
+
+var revertsCommitPattern = regexp.MustCompile(`This reverts commit [0-9a-f]{4,40}\b`)
+
+func checkRevertWithoutCommit(ctx *Context) []Diagnostic {
+	msg := ctx.Message
+	if msg.Commit.Kind != RevertKind {
+		return nil
+	}
+
+	for line := 1; line < msg.End(); line++ {
+		if !msg.IsComment(line) && revertsCommitPattern.MatchString(msg.Lines[line]) {
+			return nil
+		}
+	}
+
+	diagnostic := Diagnostic{
+		Range: helper.LineRange(0, 0, len(msg.Header.Text)),
+		Type:  RevertWithoutCommitWarning,
+		Args:  []string{msg.Commit.Target},
+	}
+
+	// Git puts the sentence in the first paragraph of the body
+	for _, entry := range branchLog(ctx.Repo) {
+		if entry.Subject == msg.Commit.Target {
+			end := helper.LineRange(0, len(msg.Header.Text), len(msg.Header.Text))
+			diagnostic.Fixes = []Fix{{
+				Title: fmt.Sprintf("Insert \"This reverts commit %s.\"", entry.Hash[:7]),
+				Edits: []lsp.TextEdit{{Range: end, NewText: fmt.Sprintf("\n\nThis reverts commit %s.", entry.Hash)}},
+			}}
+			break
+		}
+	}
+
+	return []Diagnostic{diagnostic}
+}
+
+func checkUnknownAutosquashTarget(ctx *Context) []Diagnostic {
+	commit := ctx.Message.Commit
+	if commit.Kind != FixupKind && commit.Kind != SquashKind && commit.Kind != AmendKind {
+		return nil
+	}
+
+	entries := branchLog(ctx.Repo)
+	if entries == nil {
+		return nil
+	}
+
+	// Like `git rebase --autosquash`, the target may be the start of the subject, or a hash
+	target := autosquashSubject(commit.Target)
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Subject, target) || (len(target) >= 4 && strings.HasPrefix(entry.Hash, target)) {
+			return nil
+		}
+	}
+
+	return []Diagnostic{{
+		Range: ctx.Message.Header.TargetRange,
+		Type:  UnknownAutosquashTargetWarning,
+		Args:  []string{commit.Target},
+	}}
+}
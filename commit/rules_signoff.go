@@ -0,0 +1,114 @@
+package commit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/git"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// Diagnostic error/warning types
+const (
+	// There is no Signed-off-by trailer for the committer (only if enabled with requireSignOff)
+	// Args: 0 = committer ("Name <email>"), or "" if it is unknown
+	MissingSignOffError DiagnosticType = "trailer/sign-off"
+)
+
+const SignOffKey = "Signed-off-by"
+
+func init() {
+	Register(NewRule(RuleInfo{
+		ID:          MissingSignOffError,
+		Description: "The message must have a Signed-off-by trailer for the committer (if requireSignOff is enabled)",
+		Severity:    lsp.DiagnosticSeverityError,
+		DocURL:      "https://developercertificate.org/",
+		Message:     missingSignOffMessage,
+	}, checkSignOff))
+}
+
+// Edit that adds a trailer at the end of the trailer block, where `git commit --signoff` adds
+// it. If there is no trailer block, a new paragraph is added
+func (self *Message) addTrailerEdit(trailers []Trailer, trailer string) lsp.TextEdit {
+	if len(trailers) > 0 {
+		end := trailers[len(trailers)-1].Range.End
+		return lsp.TextEdit{Range: lsp.Range{Start: end, End: end}, NewText: "\n" + trailer}
+	}
+
+	last := self.lastContentLine()
+	return lsp.TextEdit{
+		Range:   helper.LineRange(last, len(self.Lines[last]), len(self.Lines[last])),
+		NewText: "\n\n" + trailer,
+	}
+}
+
+func (self *Message) signOffFix(trailers []Trailer, committer git.Identity) Fix {
+	return Fix{
+		Title: fmt.Sprintf("Sign off as %s", committer),
+		Edits: []lsp.TextEdit{self.addTrailerEdit(trailers, fmt.Sprintf("%s: %s", SignOffKey, committer))},
+	}
+}
+
+func missingSignOffMessage(diagnostic Diagnostic) string {
+	if len(diagnostic.Args) > 0 && diagnostic.Args[0] != "" {
+		return fmt.Sprintf("Missing 'Signed-off-by: %s'", diagnostic.Args[0])
+	}
+	return "Missing Signed-off-by trailer"
+}
+
+func checkSignOff(ctx *Context) []Diagnostic {
+	if !ctx.config().RequireSignOff {
+		return nil
+	}
+
+	msg := ctx.Message
+	trailers := msg.Trailers(ctx.trailerSeparators())
+	committer, knownCommitter := git.Committer(ctx.Repo.Root)
+
+	signOffs := []Trailer{}
+	for _, trailer := range trailers {
+		if !strings.EqualFold(trailer.Key, SignOffKey) {
+			continue
+		}
+		signOffs = append(signOffs, trailer)
+
+		identity, ok := git.ParseIdentity(trailer.Value)
+		if !knownCommitter || (ok && identity.Matches(committer)) {
+			// Any sign-off is accepted if the committer is unknown
+			return nil
+		}
+	}
+
+	args := []string{""}
+	if knownCommitter {
+		args = []string{committer.String()}
+	}
+
+	if len(signOffs) == 0 {
+		diagnostic := Diagnostic{
+			Range: helper.LineRange(0, 0, len(msg.Header.Text)),
+			Type:  MissingSignOffError,
+			Args:  args,
+		}
+		if knownCommitter {
+			diagnostic.Fixes = []Fix{msg.signOffFix(trailers, committer)}
+		}
+		return []Diagnostic{diagnostic}
+	}
+
+	// There are sign-offs, but none of them is the committer
+	last := signOffs[len(signOffs)-1]
+	return []Diagnostic{{
+		Range: last.ValueRange,
+		Type:  MissingSignOffError,
+		Args:  args,
+		Fixes: []Fix{
+			msg.signOffFix(trailers, committer),
+			{
+				Title: fmt.Sprintf("Replace with %s", committer),
+				Edits: []lsp.TextEdit{{Range: last.ValueRange, NewText: committer.String()}},
+			},
+		},
+	}}
+}
@@ -0,0 +1,117 @@
+package commit
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRules(t *testing.T) {
+	rules := Rules()
+	require.NotEmpty(t, rules)
+
+	seen := map[DiagnosticType]bool{}
+	for i, rule := range rules {
+		assert.False(t, seen[rule.ID], "duplicate rule ID %s", rule.ID)
+		seen[rule.ID] = true
+
+		assert.NotEmpty(t, rule.Description, rule.ID)
+		assert.NotZero(t, rule.Severity, rule.ID)
+		if i > 0 {
+			assert.Less(t, rules[i-1].ID, rule.ID)
+		}
+	}
+
+	_, ok := LookupRule("not/a-rule")
+	assert.False(t, ok)
+}
+
+func TestToLspDiagnostic(t *testing.T) {
+	diagnostic := Diagnostic{
+		Range: helper.LineRange(0, 4, 6),
+		Type:  EmptyScopeError,
+	}.ToLspDiagnostic()
+
+	assert.Equal(t, "header/empty-scope", diagnostic.Code)
+	assert.Equal(t, lsp.DiagnosticSeverityError, diagnostic.Severity)
+	require.NotNil(t, diagnostic.CodeDescription)
+	assert.Equal(t, conventionalCommitsSpecURL, diagnostic.CodeDescription.Href)
+
+	// Diagnostics without the arguments of their message (e.g. from external rules) fall back to
+	// their own message, or the description of the rule
+	rule, _ := LookupRule(ExtraCharactersAfterScopeError)
+	assert.Equal(t, rule.Description, Diagnostic{Type: ExtraCharactersAfterScopeError}.ToLspDiagnostic().Message)
+	assert.Equal(t, "custom", Diagnostic{Type: TypeCaseWarning, Args: []string{"Feat"}, Message: "custom"}.ToLspDiagnostic().Message)
+}
+
+func TestRegister(t *testing.T) {
+	rule := NewRule(RuleInfo{
+		ID:          "test/no-wip",
+		Description: "The description must not contain WIP",
+		Severity:    lsp.DiagnosticSeverityWarning,
+	}, func(ctx *Context) []Diagnostic {
+		if ctx.Message.Commit.Description != "WIP" {
+			return nil
+		}
+
+		return []Diagnostic{{
+			Range:   ctx.Message.Header.DescriptionRange,
+			Type:    "test/no-wip",
+			Message: "Work in progress",
+		}}
+	})
+	Register(rule)
+	assert.Panics(t, func() { Register(rule) })
+
+	info, ok := LookupRule("test/no-wip")
+	require.True(t, ok)
+	assert.Equal(t, lsp.DiagnosticSeverityWarning, info.Severity)
+
+	_, diagnostics := Parse("feat: WIP")
+	require.Len(t, diagnostics, 1)
+	lspDiagnostic := diagnostics[0].ToLspDiagnostic()
+	assert.Equal(t, "Work in progress", lspDiagnostic.Message)
+	assert.Equal(t, lsp.DiagnosticSeverityWarning, lspDiagnostic.Severity)
+	assert.Equal(t, helper.LineRange(0, 6, 9), lspDiagnostic.Range)
+}
+
+func TestRegisterInfo(t *testing.T) {
+	RegisterInfo(RuleInfo{
+		ID:          "test/reported-elsewhere",
+		Description: "Reported outside of Check",
+		Severity:    lsp.DiagnosticSeverityHint,
+	})
+	assert.Panics(t, func() { RegisterInfo(RuleInfo{ID: "test/reported-elsewhere"}) })
+	assert.Panics(t, func() { Register(NewRule(RuleInfo{ID: "test/reported-elsewhere"}, nil)) })
+
+	info, ok := LookupRule("test/reported-elsewhere")
+	require.True(t, ok)
+	assert.Equal(t, lsp.DiagnosticSeverityHint, info.Severity)
+	assert.True(t, slices.ContainsFunc(Rules(), func(rule RuleInfo) bool { return rule.ID == "test/reported-elsewhere" }))
+
+	_, ok = LookupRule(UnusedSuppressionWarning)
+	assert.True(t, ok)
+}
+
+func TestCheckConfiguredRules(t *testing.T) {
+	cfg := config.Default()
+	check := func(text string) []Diagnostic {
+		return Check(&Context{Message: ParseMessage(text), Config: cfg})
+	}
+	require.Len(t, check("feat: Added x."), 3)
+
+	cfg.Rules = map[string]string{
+		string(DescriptionCaseWarning):           config.RuleOff,
+		string(DescriptionTrailingPeriodWarning): "off",
+		string(DescriptionNotImperativeWarning):  "hint",
+	}
+	diagnostics := check("feat: Added x.")
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, DescriptionNotImperativeWarning, diagnostics[0].Type)
+	assert.Equal(t, lsp.DiagnosticSeverityHint, diagnostics[0].ToLspDiagnostic().Severity)
+}
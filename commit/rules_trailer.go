@@ -0,0 +1,247 @@
+package commit
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// Rules for git trailers (see Trailer)
+
+// Diagnostic error/warning types
+const (
+	// A trailer appears more than once, but should be unique (or has the same value twice)
+	// Args: 0 = key
+	DuplicateTrailerWarning DiagnosticType = "trailer/duplicate"
+	// A paragraph of trailers is not the last paragraph, so git ignores it
+	TrailerNotInLastParagraphWarning DiagnosticType = "trailer/not-in-last-paragraph"
+	// The value of a trailer is not "Name <email>"
+	// Args: 0 = key
+	MalformedIdentityTrailerWarning DiagnosticType = "trailer/malformed-identity"
+	// The key of a trailer is not one of the configured keys
+	// Args: 0 = key
+	UnknownTrailerKeyWarning DiagnosticType = "trailer/unknown-key"
+)
+
+const interpretTrailersDocURL = "https://git-scm.com/docs/git-interpret-trailers"
+
+func init() {
+	Register(NewRule(RuleInfo{
+		ID:          DuplicateTrailerWarning,
+		Description: "Trailers that must be unique must only appear once, and no trailer should be repeated with the same value",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		DocURL:      interpretTrailersDocURL,
+		Message:     formatMessage("Duplicate '%s' trailer", 1),
+	}, checkDuplicateTrailers))
+	Register(NewRule(RuleInfo{
+		ID:          TrailerNotInLastParagraphWarning,
+		Description: "Trailers must be in the last paragraph of the message, or git ignores them",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		DocURL:      interpretTrailersDocURL,
+		Message:     staticMessage("Trailers must be in the last paragraph, git ignores these"),
+	}, checkTrailersNotInLastParagraph))
+	Register(NewRule(RuleInfo{
+		ID:          MalformedIdentityTrailerWarning,
+		Description: "Trailers for people (e.g. Signed-off-by) must have a \"Name <email>\" value",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		DocURL:      interpretTrailersDocURL,
+		Message:     formatMessage("'%s' must be \"Name <email>\"", 1),
+	}, checkMalformedIdentityTrailers))
+	Register(NewRule(RuleInfo{
+		ID:          UnknownTrailerKeyWarning,
+		Description: "Trailer keys must be one of the configured keys (if any are configured)",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		DocURL:      interpretTrailersDocURL,
+		Message:     formatMessage("Unknown trailer '%s'", 1),
+	}, checkUnknownTrailerKeys))
+}
+
+func containsFold(list []string, s string) bool {
+	return slices.ContainsFunc(list, func(item string) bool {
+		return strings.EqualFold(item, s)
+	})
+}
+
+func checkDuplicateTrailers(ctx *Context) []Diagnostic {
+	unique := ctx.config().Trailers.Unique
+
+	diagnostics := []Diagnostic{}
+	trailers := ctx.Message.Trailers(ctx.trailerSeparators())
+	for i, trailer := range trailers {
+		for _, previous := range trailers[:i] {
+			if !strings.EqualFold(previous.Key, trailer.Key) {
+				continue
+			}
+
+			if previous.Value == trailer.Value || containsFold(unique, trailer.Key) {
+				diagnostics = append(diagnostics, Diagnostic{
+					Range: trailer.Range,
+					Type:  DuplicateTrailerWarning,
+					Args:  []string{trailer.Key},
+					Fixes: []Fix{{
+						Title: "Remove duplicate trailer",
+						Edits: []lsp.TextEdit{{Range: lineDeletionRange(trailer.Range), NewText: ""}},
+					}},
+				})
+				break
+			}
+		}
+	}
+
+	return diagnostics
+}
+
+// Range that deletes the lines of a range, including the line ending
+func lineDeletionRange(r lsp.Range) lsp.Range {
+	return lsp.Range{
+		Start: lsp.Position{Line: r.Start.Line, Character: 0},
+		End:   lsp.Position{Line: r.End.Line + 1, Character: 0},
+	}
+}
+
+// The last line before the comments, which is where new trailers are added
+func (self *Message) lastContentLine() int {
+	last := 0
+	for line := 1; line < self.End(); line++ {
+		if !self.IsComment(line) && strings.TrimSpace(self.Lines[line]) != "" {
+			last = line
+		}
+	}
+	return last
+}
+
+// Whether the key is most likely meant to be a trailer, and not the start of a sentence in the
+// body (e.g. "Note: ...")
+func isTrailerKey(cfg *config.Config, key string) bool {
+	trailers := cfg.Trailers
+	return strings.Contains(key, "-") || containsFold(trailers.Keys, key) ||
+		containsFold(trailers.Unique, key) || containsFold(trailers.Identity, key)
+}
+
+func checkTrailersNotInLastParagraph(ctx *Context) []Diagnostic {
+	msg := ctx.Message
+	if len(msg.paragraphs) < 2 {
+		return nil
+	}
+
+	separators := ctx.trailerSeparators()
+	_, lastIsTrailerBlock := msg.parseTrailerBlock(msg.paragraphs[len(msg.paragraphs)-1], separators)
+	end := msg.lastContentLine()
+
+	diagnostics := []Diagnostic{}
+	for i, paragraph := range msg.paragraphs[:len(msg.paragraphs)-1] {
+		trailers, ok := msg.parseTrailerBlock(paragraph, separators)
+		if !ok || !slices.ContainsFunc(trailers, func(trailer Trailer) bool {
+			return isTrailerKey(ctx.config(), trailer.Key)
+		}) {
+			continue
+		}
+
+		lines := []string{}
+		for _, line := range paragraph {
+			lines = append(lines, msg.Lines[line])
+		}
+		insert := "\n" + strings.Join(lines, "\n")
+		if !lastIsTrailerBlock {
+			insert = "\n" + insert
+		}
+
+		first, last := paragraph[0], paragraph[len(paragraph)-1]
+		fix := Fix{
+			Title: "Move trailers to the footer block",
+			// Remove the paragraph and the blank line after it, but keep the comments
+			Edits: append(msg.removeLines(msg.withBlankLineAfter(paragraph, msg.paragraphs[i+1][0])), lsp.TextEdit{
+				Range:   helper.LineRange(end, len(msg.Lines[end]), len(msg.Lines[end])),
+				NewText: insert,
+			}),
+		}
+
+		diagnostics = append(diagnostics, Diagnostic{
+			Range: lsp.Range{
+				Start: lsp.Position{Line: first, Character: 0},
+				End:   lsp.Position{Line: last, Character: len(msg.Lines[last])},
+			},
+			Type:  TrailerNotInLastParagraphWarning,
+			Fixes: []Fix{fix},
+		})
+	}
+
+	return diagnostics
+}
+
+// The lines of the paragraph, and the first blank line after it (before the next paragraph,
+// which starts at next). Comment lines are not included
+func (self *Message) withBlankLineAfter(paragraph []int, next int) []int {
+	lines := slices.Clone(paragraph)
+	for line := paragraph[len(paragraph)-1] + 1; line < next; line++ {
+		if !self.IsComment(line) {
+			return append(lines, line)
+		}
+	}
+	return lines
+}
+
+// Edits that remove the whole lines, merging runs of consecutive lines into one edit
+func (self *Message) removeLines(lines []int) []lsp.TextEdit {
+	edits := []lsp.TextEdit{}
+	for i, line := range lines {
+		if i > 0 && line == lines[i-1]+1 {
+			edits[len(edits)-1].Range.End.Line = line + 1
+			continue
+		}
+		edits = append(edits, lsp.TextEdit{
+			Range: lsp.Range{
+				Start: lsp.Position{Line: line, Character: 0},
+				End:   lsp.Position{Line: line + 1, Character: 0},
+			},
+			NewText: "",
+		})
+	}
+	return edits
+}
+
+// "Name <email>", where the name is optional
+var identityPattern = regexp.MustCompile(`^(?:[^<>]+ )?<[^<>@\s]+@[^<>\s]+>$`)
+
+func checkMalformedIdentityTrailers(ctx *Context) []Diagnostic {
+	identity := ctx.config().Trailers.Identity
+
+	diagnostics := []Diagnostic{}
+	for _, trailer := range ctx.Message.Trailers(ctx.trailerSeparators()) {
+		if containsFold(identity, trailer.Key) && !identityPattern.MatchString(trailer.Value) {
+			diagnostics = append(diagnostics, Diagnostic{
+				Range: trailer.ValueRange,
+				Type:  MalformedIdentityTrailerWarning,
+				Args:  []string{trailer.Key},
+			})
+		}
+	}
+
+	return diagnostics
+}
+
+func checkUnknownTrailerKeys(ctx *Context) []Diagnostic {
+	keys := ctx.config().Trailers.Keys
+	if len(keys) == 0 {
+		return nil
+	}
+
+	diagnostics := []Diagnostic{}
+	for _, trailer := range ctx.Message.Trailers(ctx.trailerSeparators()) {
+		if containsFold(keys, trailer.Key) {
+			continue
+		}
+
+		diagnostics = append(diagnostics, Diagnostic{
+			Range: trailer.KeyRange,
+			Type:  UnknownTrailerKeyWarning,
+			Args:  []string{trailer.Key},
+		})
+	}
+
+	return diagnostics
+}
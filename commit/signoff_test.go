@@ -0,0 +1,52 @@
+package commit
+
+import (
+	"testing"
+
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignOff(t *testing.T) {
+	t.Setenv("GIT_COMMITTER_NAME", "Jo Doe")
+	t.Setenv("GIT_COMMITTER_EMAIL", "jo@example.com")
+
+	cfg := config.Default()
+	check := func(text string) []Diagnostic {
+		return Check(&Context{Message: ParseMessage(text), Config: cfg})
+	}
+
+	// Disabled by default
+	assert.Empty(t, check("feat: x"))
+
+	cfg.RequireSignOff = true
+	assert.Empty(t, check("feat: x\n\nRefs: #1\nSigned-off-by: Jo Doe <JO@example.com>"))
+
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(0, 0, 7),
+		Type:  MissingSignOffError,
+		Args:  []string{"Jo Doe <jo@example.com>"},
+		Fixes: []Fix{{
+			Title: "Sign off as Jo Doe <jo@example.com>",
+			Edits: []lsp.TextEdit{{Range: helper.LineRange(2, 4, 4), NewText: "\n\nSigned-off-by: Jo Doe <jo@example.com>"}},
+		}},
+	}}, check("feat: x\n\nBody\n# comment"))
+
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(0, 0, 7),
+		Type:  MissingSignOffError,
+		Args:  []string{"Jo Doe <jo@example.com>"},
+		Fixes: []Fix{{
+			Title: "Sign off as Jo Doe <jo@example.com>",
+			Edits: []lsp.TextEdit{{Range: helper.LineRange(3, 39, 39), NewText: "\nSigned-off-by: Jo Doe <jo@example.com>"}},
+		}},
+	}}, check("feat: x\n\nRefs: #1\nAcked-by: Someone <someone@example.com>"))
+
+	diagnostics := check("feat: x\n\nSigned-off-by: Other <other@example.com>\nRefs: #1")
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, helper.LineRange(2, 15, 40), diagnostics[0].Range)
+	assert.Equal(t, []lsp.TextEdit{{Range: helper.LineRange(3, 8, 8), NewText: "\nSigned-off-by: Jo Doe <jo@example.com>"}}, diagnostics[0].Fixes[0].Edits)
+	assert.Equal(t, []lsp.TextEdit{{Range: helper.LineRange(2, 15, 40), NewText: "Jo Doe <jo@example.com>"}}, diagnostics[0].Fixes[1].Edits)
+}
@@ -0,0 +1,68 @@
+package commit
+
+import (
+	"slices"
+	"strings"
+)
+
+// Edit distance between two strings (optimal string alignment), so that a swap of two adjacent
+// characters (e.g. "feta" and "feat") counts as one edit
+func editDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// distances[i][j] is the distance between the first i runes of a and the first j runes of b
+	distances := make([][]int, len(ra)+1)
+	for i := range distances {
+		distances[i] = make([]int, len(rb)+1)
+		distances[i][0] = i
+	}
+	for j := range distances[0] {
+		distances[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			distances[i][j] = min(
+				distances[i-1][j]+1,
+				distances[i][j-1]+1,
+				distances[i-1][j-1]+cost,
+			)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				distances[i][j] = min(distances[i][j], distances[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return distances[len(ra)][len(rb)]
+}
+
+// How many suggestions are made for an unknown word
+const maxSuggestions = 3
+
+// The candidates that are close to the word (ignoring case), closest first
+func Suggestions(word string, candidates []string) []string {
+	word = strings.ToLower(word)
+	// Short words are only a few edits away from any other short word
+	maxDistance := min(2, max(1, len([]rune(word))/3))
+
+	distances := map[string]int{}
+	suggested := []string{}
+	for _, candidate := range candidates {
+		distance := editDistance(word, strings.ToLower(candidate))
+		if _, ok := distances[candidate]; ok || distance > maxDistance {
+			continue
+		}
+		distances[candidate] = distance
+		suggested = append(suggested, candidate)
+	}
+
+	slices.SortStableFunc(suggested, func(a, b string) int {
+		return distances[a] - distances[b]
+	})
+
+	return suggested[:min(len(suggested), maxSuggestions)]
+}
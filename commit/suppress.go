@@ -0,0 +1,175 @@
+package commit
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// Comment that disables rules for the whole message: "# git-lsp-disable rule-id[, rule-id...]"
+// Git removes comments before committing, so it doesn't end up in the commit
+const SuppressionDirective = "git-lsp-disable"
+
+// Diagnostic error/warning types
+const (
+	// A suppression comment referred to a rule that doesn't exist
+	// Args: 0 = rule ID
+	UnknownSuppressedRuleWarning DiagnosticType = "suppression/unknown-rule"
+	// A suppression comment disabled a rule that didn't report anything
+	// Args: 0 = rule ID
+	UnusedSuppressionWarning DiagnosticType = "suppression/unused"
+)
+
+// A rule disabled by a suppression comment
+type Suppression struct {
+	Rule DiagnosticType
+	// Range of the rule ID in the comment
+	Range lsp.Range
+	// Line of the comment
+	Line int
+}
+
+func init() {
+	// These are reported by Suppress, after all other rules have been run
+	RegisterInfo(RuleInfo{
+		ID:          UnknownSuppressedRuleWarning,
+		Description: "Suppression comments must refer to existing rules",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		Message:     formatMessage("Unknown rule '%s'", 1),
+	})
+	RegisterInfo(RuleInfo{
+		ID:          UnusedSuppressionWarning,
+		Description: "Suppression comments must disable a rule that reports a problem",
+		Severity:    lsp.DiagnosticSeverityWarning,
+		Message:     formatMessage("'%s' is disabled, but did not report any problems", 1),
+	})
+}
+
+// Parse a suppression comment line. Returns nil if it isn't one
+func parseSuppressions(line int, text string) []Suppression {
+	rest, ok := strings.CutPrefix(text, CommentChar)
+	if !ok {
+		return nil
+	}
+	rest = strings.TrimLeft(rest, " \t")
+	rest, ok = strings.CutPrefix(rest, SuppressionDirective)
+	if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return nil
+	}
+
+	suppressions := []Suppression{}
+	offset := len(text) - len(rest)
+	for _, field := range strings.FieldsFunc(rest, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == ','
+	}) {
+		start := offset + strings.Index(text[offset:], field)
+		offset = start + len(field)
+
+		suppressions = append(suppressions, Suppression{
+			Rule:  DiagnosticType(field),
+			Range: helper.LineRange(line, start, offset),
+			Line:  line,
+		})
+	}
+
+	return suppressions
+}
+
+// Remove the diagnostics of rules that are disabled by suppression comments, and report suppressions
+// that refer to unknown rules or are unused
+//
+// knownRules are the IDs of rules that are not registered (e.g. external rules) but may be disabled
+func Suppress(msg *Message, diagnostics []Diagnostic, knownRules []DiagnosticType) []Diagnostic {
+	if len(msg.Suppressions) == 0 {
+		return diagnostics
+	}
+
+	used := make([]bool, len(msg.Suppressions))
+	result := []Diagnostic{}
+	for _, diagnostic := range diagnostics {
+		suppressed := false
+		for i, suppression := range msg.Suppressions {
+			if suppression.Rule == diagnostic.Type {
+				used[i] = true
+				suppressed = true
+			}
+		}
+
+		if !suppressed {
+			result = append(result, diagnostic)
+		}
+	}
+
+	for i, suppression := range msg.Suppressions {
+		if used[i] {
+			continue
+		}
+
+		if _, ok := LookupRule(suppression.Rule); !ok && !slices.Contains(knownRules, suppression.Rule) {
+			result = append(result, Diagnostic{
+				Range: suppression.Range,
+				Type:  UnknownSuppressedRuleWarning,
+				Args:  []string{string(suppression.Rule)},
+			})
+			continue
+		}
+
+		diagnostic := Diagnostic{
+			Range: suppression.Range,
+			Type:  UnusedSuppressionWarning,
+			Args:  []string{string(suppression.Rule)},
+		}
+		onLine := slices.ContainsFunc(msg.Suppressions, func(s Suppression) bool {
+			return s.Line == suppression.Line && s.Rule != suppression.Rule
+		})
+		if !onLine {
+			diagnostic.Fixes = []Fix{{
+				Title: "Remove unused suppression",
+				Edits: []lsp.TextEdit{{
+					Range: lsp.Range{
+						Start: lsp.Position{Line: suppression.Line, Character: 0},
+						End:   lsp.Position{Line: suppression.Line + 1, Character: 0},
+					},
+					NewText: "",
+				}},
+			}}
+		}
+		result = append(result, diagnostic)
+	}
+
+	return result
+}
+
+// A fix that adds a suppression comment for the rule, after the content of the message
+func (self *Message) SuppressionFix(rule DiagnosticType) Fix {
+	comment := fmt.Sprintf("%s %s %s", CommentChar, SuppressionDirective, rule)
+
+	// Insert after the last non-blank, non-comment line
+	line := self.End()
+	for line > 1 && (self.IsComment(line-1) || strings.TrimSpace(self.Lines[line-1]) == "") {
+		line--
+	}
+
+	var edit lsp.TextEdit
+	if line >= len(self.Lines) {
+		// There is no line to insert before
+		last := len(self.Lines) - 1
+		edit = lsp.TextEdit{
+			Range:   helper.LineRange(last, len(self.Lines[last]), len(self.Lines[last])),
+			NewText: "\n" + comment,
+		}
+	} else {
+		edit = lsp.TextEdit{
+			Range:   helper.LineRange(line, 0, 0),
+			NewText: comment + "\n",
+		}
+	}
+
+	return Fix{
+		Title: fmt.Sprintf("Disable %s for this message", rule),
+		Edits: []lsp.TextEdit{edit},
+	}
+}
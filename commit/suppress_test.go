@@ -0,0 +1,80 @@
+package commit
+
+import (
+	"testing"
+
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSuppressions(t *testing.T) {
+	assert.Nil(t, parseSuppressions(1, "# Please enter the commit message"))
+	assert.Nil(t, parseSuppressions(1, "# git-lsp-disabled header/empty-scope"))
+	assert.Empty(t, parseSuppressions(1, "# git-lsp-disable"))
+
+	assert.Equal(t, []Suppression{
+		{Rule: EmptyScopeError, Range: helper.LineRange(3, 18, 36), Line: 3},
+		{Rule: "team/rule", Range: helper.LineRange(3, 38, 47), Line: 3},
+	}, parseSuppressions(3, "# git-lsp-disable header/empty-scope, team/rule"))
+}
+
+func TestSuppress(t *testing.T) {
+	commitMsg := "feat(): description\n" +
+		"\n" +
+		"# git-lsp-disable header/empty-scope team/external\n" +
+		"# git-lsp-disable header/empty-type\n" +
+		"#git-lsp-disable not/a-rule\n"
+
+	_, diagnostics := Parse(commitMsg)
+	assert.ElementsMatch(t, []Diagnostic{
+		{
+			Range: helper.LineRange(2, 37, 50),
+			Type:  UnknownSuppressedRuleWarning,
+			Args:  []string{"team/external"},
+		},
+		{
+			Range: helper.LineRange(3, 18, 35),
+			Type:  UnusedSuppressionWarning,
+			Args:  []string{"header/empty-type"},
+			Fixes: []Fix{{
+				Title: "Remove unused suppression",
+				Edits: []lsp.TextEdit{{
+					Range: lsp.Range{
+						Start: lsp.Position{Line: 3, Character: 0},
+						End:   lsp.Position{Line: 4, Character: 0},
+					},
+					NewText: "",
+				}},
+			}},
+		},
+		{
+			Range: helper.LineRange(4, 17, 27),
+			Type:  UnknownSuppressedRuleWarning,
+			Args:  []string{"not/a-rule"},
+		},
+	}, diagnostics)
+
+	// Rules that are not registered, but are known (e.g. external rules)
+	msg := ParseMessage(commitMsg)
+	diagnostics = Suppress(msg, []Diagnostic{}, []DiagnosticType{"team/external"})
+	assert.Equal(t, UnusedSuppressionWarning, diagnostics[0].Type)
+	assert.Nil(t, diagnostics[0].Fixes)
+}
+
+func TestSuppressionFix(t *testing.T) {
+	fix := ParseMessage("type(): description").SuppressionFix(EmptyScopeError)
+	assert.Equal(t, "Disable header/empty-scope for this message", fix.Title)
+	assert.Equal(t, []lsp.TextEdit{{
+		Range:   helper.LineRange(0, 19, 19),
+		NewText: "\n# git-lsp-disable header/empty-scope",
+	}}, fix.Edits)
+
+	fix = ParseMessage("type(): description\n\nBody\n\n# Comment\n").SuppressionFix(EmptyScopeError)
+	require.Len(t, fix.Edits, 1)
+	assert.Equal(t, lsp.TextEdit{
+		Range:   helper.LineRange(3, 0, 0),
+		NewText: "# git-lsp-disable header/empty-scope\n",
+	}, fix.Edits[0])
+}
@@ -0,0 +1,163 @@
+package commit
+
+import (
+	"strings"
+
+	"github.com/eamonburns/git-lsp/git"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// Git trailers are parsed like `git interpret-trailers` does, which is slightly different from
+// the Conventional Commits footers (see ParseFooter):
+//   - The key may only contain letters, digits and '-', and may be followed by whitespace
+//     before the separator (e.g. "Signed-off-by : A <a@b>")
+//   - The separators are configured with trailer.separators (":" by default)
+//   - Keys are case-insensitive
+//   - Lines starting with whitespace continue the value of the previous trailer
+//
+// https://git-scm.com/docs/git-interpret-trailers
+
+// Value of trailer.separators if it is not configured
+const DefaultTrailerSeparators = ":"
+
+// Prefixes of lines added by git. A paragraph that contains one of them only needs 25% of its
+// lines to be trailers to be a trailer block
+var gitGeneratedTrailerPrefixes = []string{"Signed-off-by: ", "(cherry picked from commit "}
+
+type Trailer struct {
+	Key   string
+	Value string
+	// One of the trailer separators (e.g. ":")
+	Separator string
+
+	// Range of the whole trailer, including continuation lines
+	Range      lsp.Range
+	KeyRange   lsp.Range
+	ValueRange lsp.Range
+}
+
+// Parse a single line as a trailer, with any of the separators
+//
+// Returns the key, the value and the separator, or false if the line is not a trailer
+func ParseTrailer(text string, separators string) (string, string, string, bool) {
+	whitespace := false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if strings.IndexByte(separators, c) != -1 {
+			if i == 0 {
+				return "", "", "", false
+			}
+			return strings.TrimRight(text[:i], " \t"), strings.TrimSpace(text[i+1:]), string(c), true
+		}
+
+		isTokenChar := ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') || c == '-'
+		if !whitespace && isTokenChar {
+			continue
+		}
+		if i > 0 && (c == ' ' || c == '\t') {
+			whitespace = true
+			continue
+		}
+		break
+	}
+
+	return "", "", "", false
+}
+
+func isContinuation(text string) bool {
+	return strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")
+}
+
+// Parse the lines of a paragraph as a trailer block
+//
+// Returns false if git would not consider the paragraph a trailer block: either all of its lines
+// are trailers, or it contains a line added by git and at least 25% of its lines are trailers
+func (self *Message) parseTrailerBlock(lines []int, separators string) ([]Trailer, bool) {
+	trailers := []Trailer{}
+	trailerLines, otherLines := 0, 0
+	recognized := false
+	// Whether the previous line was part of a trailer, so that a continuation line continues it
+	inTrailer := false
+
+	for _, line := range lines {
+		text := self.Lines[line]
+
+		for _, prefix := range gitGeneratedTrailerPrefixes {
+			if strings.HasPrefix(text, prefix) {
+				recognized = true
+			}
+		}
+
+		if isContinuation(text) {
+			if !inTrailer {
+				otherLines++
+				continue
+			}
+
+			trailerLines++
+			trailer := &trailers[len(trailers)-1]
+			trailer.Value += "\n" + strings.TrimSpace(text)
+			trailer.Range.End = lsp.Position{Line: line, Character: len(text)}
+			trailer.ValueRange.End = trailer.Range.End
+			continue
+		}
+
+		key, value, separator, ok := ParseTrailer(text, separators)
+		inTrailer = ok
+		if !ok {
+			if strings.HasPrefix(text, "(cherry picked from commit ") {
+				trailerLines++
+			} else {
+				otherLines++
+			}
+			continue
+		}
+
+		trailerLines++
+		valueStart := strings.Index(text, separator) + 1
+		valueStart += len(text[valueStart:]) - len(strings.TrimLeft(text[valueStart:], " \t"))
+		trailers = append(trailers, Trailer{
+			Key:        key,
+			Value:      value,
+			Separator:  separator,
+			Range:      helper.LineRange(line, 0, len(text)),
+			KeyRange:   helper.LineRange(line, 0, len(key)),
+			ValueRange: helper.LineRange(line, valueStart, valueStart+len(value)),
+		})
+	}
+
+	if trailerLines > 0 && otherLines == 0 {
+		return trailers, true
+	}
+	if recognized && trailerLines*3 >= otherLines {
+		return trailers, true
+	}
+	return nil, false
+}
+
+// The trailers in the last paragraph of the message, if git considers it a trailer block
+func (self *Message) Trailers(separators string) []Trailer {
+	if len(self.paragraphs) == 0 {
+		return []Trailer{}
+	}
+
+	trailers, ok := self.parseTrailerBlock(self.paragraphs[len(self.paragraphs)-1], separators)
+	if !ok {
+		return []Trailer{}
+	}
+	return trailers
+}
+
+// The separators configured in the repository with trailer.separators
+func TrailerSeparators(repo Repo) string {
+	if repo.Root == "" {
+		return DefaultTrailerSeparators
+	}
+
+	separators, ok := git.Config(repo.Root, "trailer.separators")
+	if !ok || separators == "" {
+		return DefaultTrailerSeparators
+	}
+	return separators
+}
@@ -0,0 +1,151 @@
+package commit
+
+import (
+	"testing"
+
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/internal/testrepo"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTrailer(t *testing.T) {
+	tests := []struct {
+		text       string
+		separators string
+		key        string
+		value      string
+		separator  string
+		ok         bool
+	}{
+		{"Signed-off-by: A <a@b.c>", ":", "Signed-off-by", "A <a@b.c>", ":", true},
+		{"Key : value", ":", "Key", "value", ":", true},
+		{"Key:value", ":", "Key", "value", ":", true},
+		{"Bug #123", ":#", "Bug", "123", "#", true},
+		{"Bug #123", ":", "", "", "", false},
+		{"BREAKING CHANGE: x", ":", "", "", "", false},
+		{" Key: value", ":", "", "", "", false},
+		{": value", ":", "", "", "", false},
+		{"Key_1: value", ":", "", "", "", false},
+	}
+
+	for _, test := range tests {
+		key, value, separator, ok := ParseTrailer(test.text, test.separators)
+		assert.Equal(t, test.ok, ok, test.text)
+		assert.Equal(t, test.key, key, test.text)
+		assert.Equal(t, test.value, value, test.text)
+		assert.Equal(t, test.separator, separator, test.text)
+	}
+}
+
+func TestTrailers(t *testing.T) {
+	msg := ParseMessage("feat: x\n\nBody\n\nReviewed-by: A <a@b.c>\n  and B\n# comment\nsigned-off-by : C <c@d.e>")
+	assert.Equal(t, []Trailer{
+		{
+			Key:        "Reviewed-by",
+			Value:      "A <a@b.c>\nand B",
+			Separator:  ":",
+			Range:      lsp.Range{Start: lsp.Position{Line: 4, Character: 0}, End: lsp.Position{Line: 5, Character: 7}},
+			KeyRange:   helper.LineRange(4, 0, 11),
+			ValueRange: lsp.Range{Start: lsp.Position{Line: 4, Character: 13}, End: lsp.Position{Line: 5, Character: 7}},
+		},
+		{
+			Key:        "signed-off-by",
+			Value:      "C <c@d.e>",
+			Separator:  ":",
+			Range:      helper.LineRange(7, 0, 25),
+			KeyRange:   helper.LineRange(7, 0, 13),
+			ValueRange: helper.LineRange(7, 16, 25),
+		},
+	}, msg.Trailers(DefaultTrailerSeparators))
+
+	// Not every line is a trailer
+	assert.Empty(t, ParseMessage("feat: x\n\nKey: value\nsome text").Trailers(DefaultTrailerSeparators))
+
+	// A git generated trailer only needs 25% of the lines to be trailers
+	msg = ParseMessage("feat: x\n\nSigned-off-by: A <a@b.c>\nsome text\nmore text\neven more")
+	assert.Len(t, msg.Trailers(DefaultTrailerSeparators), 1)
+	msg = ParseMessage("feat: x\n\nSigned-off-by: A <a@b.c>\nsome text\nmore text\neven more\nand more")
+	assert.Empty(t, msg.Trailers(DefaultTrailerSeparators))
+
+	// The header is never a trailer block
+	assert.Empty(t, ParseMessage("Key: value").Trailers(DefaultTrailerSeparators))
+}
+
+func TestTrailerRules(t *testing.T) {
+	cfg := config.Default()
+	cfg.Trailers.Keys = []string{"Signed-off-by", "Change-Id", "Refs"}
+	cfg.Trailers.Unique = []string{"Change-Id"}
+	check := func(text string) []Diagnostic {
+		return Check(&Context{Message: ParseMessage(text), Config: cfg})
+	}
+
+	assert.Empty(t, check("feat: x\n\nRefs: #1\nRefs: #2\nChange-Id: I1\nSigned-off-by: A <a@b.c>"))
+
+	assert.Equal(t, []Diagnostic{
+		{
+			Range: helper.LineRange(3, 0, 13),
+			Type:  DuplicateTrailerWarning,
+			Args:  []string{"change-id"},
+			Fixes: []Fix{{
+				Title: "Remove duplicate trailer",
+				Edits: []lsp.TextEdit{{Range: lsp.Range{Start: lsp.Position{Line: 3}, End: lsp.Position{Line: 4}}, NewText: ""}},
+			}},
+		},
+		{
+			Range: helper.LineRange(5, 0, 8),
+			Type:  DuplicateTrailerWarning,
+			Args:  []string{"Refs"},
+			Fixes: []Fix{{
+				Title: "Remove duplicate trailer",
+				Edits: []lsp.TextEdit{{Range: lsp.Range{Start: lsp.Position{Line: 5}, End: lsp.Position{Line: 6}}, NewText: ""}},
+			}},
+		},
+		{Range: helper.LineRange(6, 15, 16), Type: MalformedIdentityTrailerWarning, Args: []string{"Signed-off-by"}},
+		{Range: helper.LineRange(7, 0, 5), Type: UnknownTrailerKeyWarning, Args: []string{"Fixes"}},
+	}, check("feat: x\n\nChange-Id: I1\nchange-id: I2\nRefs: #1\nRefs: #1\nSigned-off-by: A\nFixes: #3"))
+}
+
+func TestTrailerNotInLastParagraph(t *testing.T) {
+	text := "feat: x\n\nSigned-off-by: A <a@b.c>\n\nNote: a sentence\n\nBody\n\nRefs: #1"
+	move := lsp.TextEdit{Range: helper.LineRange(8, 8, 8), NewText: "\nSigned-off-by: A <a@b.c>"}
+	assert.Equal(t, []Diagnostic{{
+		Range: helper.LineRange(2, 0, 24),
+		Type:  TrailerNotInLastParagraphWarning,
+		Fixes: []Fix{{
+			Title: "Move trailers to the footer block",
+			Edits: []lsp.TextEdit{
+				{Range: lsp.Range{Start: lsp.Position{Line: 2}, End: lsp.Position{Line: 4}}, NewText: ""},
+				move,
+			},
+		}},
+	}}, Check(&Context{Message: ParseMessage(text)}))
+
+	// Without a trailer block at the end, a new one is added
+	diagnostics := Check(&Context{Message: ParseMessage("feat: x\n\nSigned-off-by: A <a@b.c>\n\nBody\n# comment")})
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, lsp.TextEdit{Range: helper.LineRange(4, 4, 4), NewText: "\n\nSigned-off-by: A <a@b.c>"}, diagnostics[0].Fixes[0].Edits[1])
+
+	// Comments between the paragraphs (e.g. suppressions) are kept
+	diagnostics = Check(&Context{Message: ParseMessage("feat: x\n\nSigned-off-by: A <a@b.c>\n# git-lsp-disable trailer/unknown-key\n\n\nBody\n\nRefs: #1")})
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, []lsp.TextEdit{
+		{Range: lsp.Range{Start: lsp.Position{Line: 2}, End: lsp.Position{Line: 3}}, NewText: ""},
+		{Range: lsp.Range{Start: lsp.Position{Line: 4}, End: lsp.Position{Line: 5}}, NewText: ""},
+		{Range: helper.LineRange(8, 8, 8), NewText: "\nSigned-off-by: A <a@b.c>"},
+	}, diagnostics[0].Fixes[0].Edits)
+}
+
+func TestContextTrailerSeparators(t *testing.T) {
+	root := testrepo.Init(t)
+	testrepo.Git(t, root, "config", "trailer.separators", "=:")
+
+	ctx := &Context{Message: ParseMessage("feat: x"), Repo: Repo{Root: root}}
+	assert.Equal(t, "=:", ctx.trailerSeparators())
+
+	// Read once for all rules
+	testrepo.Git(t, root, "config", "trailer.separators", "#")
+	assert.Equal(t, "=:", ctx.trailerSeparators())
+	assert.Equal(t, DefaultTrailerSeparators, (&Context{}).trailerSeparators())
+}
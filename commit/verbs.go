@@ -0,0 +1,111 @@
+package commit
+
+import (
+	"slices"
+	"strings"
+)
+
+// Verbs that commit descriptions commonly start with, in the imperative mood
+var imperativeVerbs = []string{
+	"add", "adjust", "allow", "apply", "avoid", "bump", "change", "check", "clarify", "clean",
+	"cleanup", "close", "configure", "convert", "copy", "correct", "create", "deduplicate",
+	"define", "delete", "deprecate", "detect", "disable", "document", "downgrade", "drop",
+	"enable", "encode", "ensure", "expose", "extend", "extract", "fix", "format", "generate",
+	"handle", "hide", "ignore", "implement", "import", "improve", "include", "increase",
+	"initialize", "inline", "install", "introduce", "limit", "load", "make", "mark",
+	"merge", "migrate", "move", "optimize", "parse", "pass", "pin", "prevent", "print",
+	"refactor", "reduce", "reformat", "release", "remove", "rename", "reorder", "replace",
+	"report", "require", "reset", "resolve", "restore", "restrict", "return", "revert", "rework",
+	"rewrite", "run", "save", "show", "simplify", "skip", "sort", "split", "start", "stop",
+	"support", "switch", "tidy", "tweak", "unify", "update", "upgrade", "use",
+	"validate", "verify", "wrap", "write",
+}
+
+// Forms of the verbs that do not follow the usual spelling rules, in addition to the regular ones
+var irregularVerbForms = map[string][]string{
+	"format":   {"formatted"},
+	"hide":     {"hid", "hidden"},
+	"make":     {"made"},
+	"reformat": {"reformatted"},
+	"rewrite":  {"rewrote", "rewritten"},
+	"run":      {"ran"},
+	"show":     {"shown"},
+	"write":    {"wrote", "written"},
+}
+
+// Verbs that have no regular past tense (e.g. "split", not "splitted")
+var irregularPastVerbs = []string{"hide", "make", "reset", "rewrite", "run", "split", "write"}
+
+// The imperative verb of each past tense or third person form (e.g. "added" and "adds" -> "add")
+var imperativeOfVerbForm = func() map[string]string {
+	forms := map[string]string{}
+	for _, verb := range imperativeVerbs {
+		for _, form := range verbForms(verb) {
+			forms[form] = verb
+		}
+	}
+	return forms
+}()
+
+func isVowel(c byte) bool {
+	return strings.IndexByte("aeiou", c) != -1
+}
+
+// Whether the last consonant is doubled in the past tense (e.g. "wrap" -> "wrapped"), which is
+// the case for verbs of one syllable that end in a consonant after a single vowel. Longer verbs
+// only double it if the last syllable is stressed, so they are in irregularVerbForms
+func doublesLastConsonant(verb string) bool {
+	n := len(verb)
+	if n < 3 || strings.IndexByte("wxy", verb[n-1]) != -1 {
+		return false
+	}
+	if isVowel(verb[n-1]) || !isVowel(verb[n-2]) || isVowel(verb[n-3]) {
+		return false
+	}
+
+	// The vowel before the last consonant is the only one
+	return strings.IndexAny(verb[:n-2], "aeiou") == -1
+}
+
+// Third person and past tense forms of a verb
+func verbForms(verb string) []string {
+	last := verb[len(verb)-1]
+	consonantY := last == 'y' && !isVowel(verb[len(verb)-2])
+
+	var thirdPerson string
+	switch {
+	case consonantY:
+		thirdPerson = verb[:len(verb)-1] + "ies"
+	case strings.HasSuffix(verb, "s"), strings.HasSuffix(verb, "x"), strings.HasSuffix(verb, "z"),
+		strings.HasSuffix(verb, "ch"), strings.HasSuffix(verb, "sh"), strings.HasSuffix(verb, "o"):
+		thirdPerson = verb + "es"
+	default:
+		thirdPerson = verb + "s"
+	}
+
+	forms := append([]string{thirdPerson}, irregularVerbForms[verb]...)
+	if slices.Contains(irregularPastVerbs, verb) {
+		return forms
+	}
+
+	var past string
+	switch {
+	case consonantY:
+		past = verb[:len(verb)-1] + "ied"
+	case last == 'e':
+		past = verb + "d"
+	case doublesLastConsonant(verb):
+		past = verb + string(last) + "ed"
+	default:
+		past = verb + "ed"
+	}
+
+	return append(forms, past)
+}
+
+// The imperative form of a verb in the past tense or third person (e.g. "added" -> "add"), or
+// false if the word is not a known form of a verb
+func imperativeOf(word string) (string, bool) {
+	verb, ok := imperativeOfVerbForm[strings.ToLower(word)]
+	return verb, ok
+}
@@ -0,0 +1,338 @@
+// Package compose writes a commit message by asking for each part of it on a terminal, and
+// checks each part with the rules as soon as it is entered
+package compose
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/git"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// The input ended before the message was complete, or the message was not confirmed
+var ErrAborted = errors.New("aborted")
+
+// Most scopes that are suggested
+const maxScopeSuggestions = 9
+
+type Composer struct {
+	Repo   commit.Repo
+	Config *config.Config
+	// Paths of the staged files, which the type and the scope are inferred from
+	Staged []string
+
+	in  *bufio.Scanner
+	out io.Writer
+}
+
+func New(in io.Reader, out io.Writer, repo commit.Repo, cfg *config.Config, staged []string) *Composer {
+	return &Composer{
+		Repo:   repo,
+		Config: cfg,
+		Staged: staged,
+		in:     bufio.NewScanner(in),
+		out:    out,
+	}
+}
+
+// The parts of the message that are asked for
+type parts struct {
+	commitType     string
+	scope          string
+	description    string
+	body           []string
+	breakingChange string
+	refs           string
+	signOff        string
+}
+
+func (self parts) message() string {
+	header := self.commitType
+	if self.scope != "" {
+		header += "(" + self.scope + ")"
+	}
+	if self.breakingChange != "" {
+		header += "!"
+	}
+	header += ": " + self.description
+
+	paragraphs := []string{header}
+	if len(self.body) > 0 {
+		paragraphs = append(paragraphs, strings.Join(self.body, "\n"))
+	}
+
+	footers := []string{}
+	if self.breakingChange != "" {
+		footers = append(footers, fmt.Sprintf("%s: %s", commit.BreakingChangeKey, self.breakingChange))
+	}
+	if self.refs != "" {
+		footers = append(footers, fmt.Sprintf("%s: %s", commit.RefsKey, self.refs))
+	}
+	if self.signOff != "" {
+		footers = append(footers, fmt.Sprintf("%s: %s", commit.SignOffKey, self.signOff))
+	}
+	if len(footers) > 0 {
+		paragraphs = append(paragraphs, strings.Join(footers, "\n"))
+	}
+
+	return strings.Join(paragraphs, "\n\n") + "\n"
+}
+
+// The line of the body that ends it
+const bodyEnd = "."
+
+// A value that can be chosen by its number
+type option struct {
+	value       string
+	description string
+}
+
+// Read a line as it was entered, or return ErrAborted if the input ended
+func (self *Composer) readLine() (string, error) {
+	if !self.in.Scan() {
+		if err := self.in.Err(); err != nil {
+			return "", err
+		}
+		return "", ErrAborted
+	}
+	return self.in.Text(), nil
+}
+
+// Ask for a value. An empty answer is the default value
+func (self *Composer) ask(prompt string, defaultValue string) (string, error) {
+	if defaultValue != "" {
+		fmt.Fprintf(self.out, "%s [%s]: ", prompt, defaultValue)
+	} else {
+		fmt.Fprintf(self.out, "%s: ", prompt)
+	}
+
+	answer, err := self.readLine()
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		answer = defaultValue
+	}
+	return answer, err
+}
+
+// Ask for a value that is one of the options (by number or value), or any other value
+func (self *Composer) choose(prompt string, options []option, defaultValue string) (string, error) {
+	width := 0
+	for _, option := range options {
+		width = max(width, len(option.value))
+	}
+	for i, option := range options {
+		fmt.Fprintf(self.out, "%3d) %-*s  %s\n", i+1, width, option.value, option.description)
+	}
+
+	answer, err := self.ask(prompt, defaultValue)
+	if n, convErr := strconv.Atoi(answer); convErr == nil && n >= 1 && n <= len(options) {
+		answer = options[n-1].value
+	}
+	return answer, err
+}
+
+// Ask a yes/no question
+func (self *Composer) confirm(prompt string, defaultValue bool) (bool, error) {
+	choices := "y/N"
+	if defaultValue {
+		choices = "Y/n"
+	}
+	fmt.Fprintf(self.out, "%s [%s]: ", prompt, choices)
+
+	answer, err := self.readLine()
+	answer = strings.TrimSpace(answer)
+	if err != nil || answer == "" {
+		return defaultValue, err
+	}
+	return strings.HasPrefix(strings.ToLower(answer), "y"), nil
+}
+
+func (self *Composer) check(text string) []commit.Diagnostic {
+	return commit.Check(&commit.Context{
+		Message: commit.ParseMessage(text),
+		Repo:    self.Repo,
+		Config:  self.Config,
+	})
+}
+
+func (self *Composer) printDiagnostics(diagnostics []commit.Diagnostic) {
+	for _, diagnostic := range diagnostics {
+		lspDiagnostic := diagnostic.ToLspDiagnostic()
+		fmt.Fprintf(self.out, "  %s: %s [%s]\n", lspDiagnostic.Severity, lspDiagnostic.Message, diagnostic.Type)
+	}
+}
+
+func hasErrors(diagnostics []commit.Diagnostic) bool {
+	return slices.ContainsFunc(diagnostics, func(diagnostic commit.Diagnostic) bool {
+		return diagnostic.ToLspDiagnostic().Severity == lsp.DiagnosticSeverityError
+	})
+}
+
+// Ask for a part of the header until it has no diagnostics of the rules with the prefixes. A
+// value with only warnings is accepted if it is entered twice in a row
+func (self *Composer) askValid(p *parts, field *string, prefixes []string, ask func() (string, error)) error {
+	previous := ""
+	for {
+		value, err := ask()
+		if err != nil {
+			return err
+		}
+		*field = value
+
+		diagnostics := []commit.Diagnostic{}
+		for _, diagnostic := range self.check(p.message()) {
+			if slices.ContainsFunc(prefixes, func(prefix string) bool {
+				return strings.HasPrefix(string(diagnostic.Type), prefix)
+			}) {
+				diagnostics = append(diagnostics, diagnostic)
+			}
+		}
+
+		if len(diagnostics) == 0 || (!hasErrors(diagnostics) && value == previous) {
+			return nil
+		}
+
+		self.printDiagnostics(diagnostics)
+		if !hasErrors(diagnostics) {
+			fmt.Fprintln(self.out, "  Enter it again to keep it")
+		}
+		previous = value
+	}
+}
+
+func (self *Composer) typeOptions() []option {
+	options := []option{}
+	for _, commitType := range self.Config.Types {
+		options = append(options, option{commitType.Name, commitType.Description})
+	}
+	return options
+}
+
+func (self *Composer) scopeOptions(ticket string) []option {
+	options := []option{}
+	add := func(scope string, description string) {
+		if scope != "" && len(options) < maxScopeSuggestions && !slices.ContainsFunc(options, func(o option) bool {
+			return o.value == scope
+		}) {
+			options = append(options, option{scope, description})
+		}
+	}
+
+	add(commit.InferScope(self.Config, self.Staged), "Staged files")
+	add(ticket, "Ticket of the current branch")
+	for _, scope := range commit.PathScopes(self.Staged) {
+		add(scope, "Changed directory")
+	}
+	for _, scope := range self.Config.Scopes {
+		add(scope, "Configured scope")
+	}
+	for _, scope := range commit.HistoryScopes(self.Repo, self.Config) {
+		add(scope, "Used before")
+	}
+	return options
+}
+
+// Ask for the body, a line at a time, until a line with only bodyEnd. Empty lines separate
+// paragraphs, and the lines are kept as they were entered (e.g. indented code)
+func (self *Composer) askBody() ([]string, error) {
+	fmt.Fprintf(self.out, "Body (end with a line with only %q):\n", bodyEnd)
+	lines := []string{}
+	for {
+		line, err := self.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(line) == bodyEnd {
+			break
+		}
+		lines = append(lines, strings.TrimRight(line, " \t\r"))
+	}
+
+	// Empty lines around the body would be empty paragraphs
+	start := slices.IndexFunc(lines, func(line string) bool { return line != "" })
+	if start == -1 {
+		return []string{}, nil
+	}
+	end := len(lines)
+	for lines[end-1] == "" {
+		end--
+	}
+	return lines[start:end], nil
+}
+
+// Ask for every part of the message, and return the message once it is confirmed
+func (self *Composer) Compose() (string, error) {
+	p := parts{}
+	ticket := commit.BranchTicket(self.Repo, self.Config)
+
+	err := self.askValid(&p, &p.commitType, []string{"type/", string(commit.EmptyTypeError)}, func() (string, error) {
+		return self.choose("Type", self.typeOptions(), commit.InferType(self.Config, self.Staged))
+	})
+	if err != nil {
+		return "", err
+	}
+
+	scopes := self.scopeOptions(ticket)
+	defaultScope := ""
+	if len(scopes) > 0 {
+		defaultScope = scopes[0].value
+	}
+	scopePrefixes := []string{"scope/", string(commit.EmptyScopeError), "header/unmatched-", string(commit.ExtraCharactersAfterScopeError)}
+	err = self.askValid(&p, &p.scope, scopePrefixes, func() (string, error) {
+		scope, err := self.choose("Scope (\"-\" for none)", scopes, defaultScope)
+		if scope == "-" {
+			scope = ""
+		}
+		return scope, err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	err = self.askValid(&p, &p.description, []string{"description/", string(commit.EmptyDescriptionError)}, func() (string, error) {
+		return self.ask("Description", "")
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if p.body, err = self.askBody(); err != nil {
+		return "", err
+	}
+	if p.breakingChange, err = self.ask("Breaking change (empty if none)", ""); err != nil {
+		return "", err
+	}
+	if p.refs, err = self.ask("Issue references", ticket); err != nil {
+		return "", err
+	}
+	if self.Config.RequireSignOff {
+		if committer, ok := git.Committer(self.Repo.Root); ok {
+			p.signOff = committer.String()
+		}
+	}
+
+	text := p.message()
+	fmt.Fprintf(self.out, "\n%s\n", text)
+
+	diagnostics := self.check(text)
+	self.printDiagnostics(diagnostics)
+	prompt := "Commit?"
+	if hasErrors(diagnostics) {
+		prompt = "The message has errors. Commit anyway?"
+	}
+	if ok, err := self.confirm(prompt, !hasErrors(diagnostics)); err != nil {
+		return "", err
+	} else if !ok {
+		return "", ErrAborted
+	}
+
+	return text, nil
+}
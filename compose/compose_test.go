@@ -0,0 +1,76 @@
+package compose
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompose(t *testing.T) {
+	cfg := config.Default()
+	cfg.Scopes = []string{"api", "ui"}
+
+	input := strings.Join([]string{
+		"7",           // Type: test (by number)
+		"",            // Scope: the inferred one
+		"",            // Description: empty is an error
+		"Added tests", // Description: only warnings
+		"add tests",   // Description: no diagnostics
+		"",            // Body: leading empty lines are dropped
+		"First line",
+		"second line",
+		"",
+		"    indented code",
+		"",
+		".",
+		"",         // No breaking change
+		"#12, #13", // Issue references
+		"",         // Commit
+	}, "\n") + "\n"
+	var out bytes.Buffer
+	composer := New(strings.NewReader(input), &out, commit.Repo{}, cfg, []string{"api/server_test.go"})
+
+	message, err := composer.Compose()
+	require.NoError(t, err)
+	assert.Equal(t, "test(api): add tests\n\nFirst line\nsecond line\n\n    indented code\n\nRefs: #12, #13\n", message)
+	assert.Contains(t, out.String(), "Scope (\"-\" for none) [api]")
+	assert.Contains(t, out.String(), "error: Empty description")
+	assert.Contains(t, out.String(), "Use the imperative mood")
+}
+
+func TestComposeWarnings(t *testing.T) {
+	cfg := config.Default()
+	cfg.Scopes = []string{"api"}
+
+	input := strings.Join([]string{
+		"feat",
+		"a)b", // Extra characters after the scope
+		"web", // Unknown scope
+		"web", // Kept
+		"add login",
+		".",
+		"drop the old session API",
+		"",
+		"y",
+	}, "\n") + "\n"
+	var out bytes.Buffer
+	message, err := New(strings.NewReader(input), &out, commit.Repo{}, cfg, nil).Compose()
+	require.NoError(t, err)
+	assert.Equal(t, "feat(web)!: add login\n\nBREAKING CHANGE: drop the old session API\n", message)
+	assert.Contains(t, out.String(), "Enter it again to keep it")
+	assert.Contains(t, out.String(), "Extra characters after scope: 'b)'")
+}
+
+func TestComposeAborted(t *testing.T) {
+	_, err := New(strings.NewReader("feat\n-\n"), &bytes.Buffer{}, commit.Repo{}, config.Default(), nil).Compose()
+	assert.ErrorIs(t, err, ErrAborted)
+
+	input := "feat\n-\nadd x\n.\n\n\nn\n"
+	_, err = New(strings.NewReader(input), &bytes.Buffer{}, commit.Repo{}, config.Default(), nil).Compose()
+	assert.ErrorIs(t, err, ErrAborted)
+}
@@ -0,0 +1,472 @@
+// Package config loads the per-repository git-lsp configuration
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// Name of the configuration file, in the root of the repository
+const FileName = ".git-lsp.json"
+
+type Config struct {
+	// Allowed commit types (e.g. "feat", "fix")
+	Types []CommitType `json:"types"`
+
+	// Allowed scopes. Any scope is allowed if this is empty and scopesFromHistory is false
+	Scopes []string `json:"scopes"`
+
+	// Whether the scopes used in the history of the branch are allowed too
+	ScopesFromHistory bool `json:"scopesFromHistory"`
+
+	// Characters that separate multiple scopes (e.g. "api,ui" or "api/auth")
+	ScopeDelimiters string `json:"scopeDelimiters"`
+
+	// Scopes that should no longer be used
+	DeprecatedScopes []string `json:"deprecatedScopes"`
+
+	// Files or directories (relative to the root of the repository) that each scope refers to
+	// Scopes that are not in the map refer to the directories with the same name
+	ScopePaths map[string][]string `json:"scopePaths"`
+
+	// Types inferred from the paths of the staged files by `git-lsp prepare`. The first pattern
+	// that matches a file decides its type
+	TypePatterns []TypePattern `json:"typePatterns"`
+
+	// Style of the description in the header
+	Description DescriptionConfig `json:"description"`
+
+	// Rules implemented by external commands
+	ExternalRules []ExternalRule `json:"externalRules"`
+
+	// Severity of rules by ID (e.g. {"description/imperative": "hint"}): "error", "warning",
+	// "information", "hint", or "off" to disable the rule
+	Rules map[string]string `json:"rules"`
+
+	// Which markers a breaking change needs: "bang" (a BREAKING CHANGE footer needs a "!" in the
+	// header), "both" (every breaking change needs the "!" and the footer) or "any"
+	BreakingChange string `json:"breakingChange"`
+
+	// Rules for git trailers ("Key: value" lines in the last paragraph)
+	Trailers TrailersConfig `json:"trailers"`
+
+	// Whether every commit must be signed off by the committer (Developer Certificate of Origin)
+	RequireSignOff bool `json:"requireSignOff"`
+
+	// Template of a new commit message, with "{type}", "{scope}", "{description}", "{body}" and
+	// "{footers}" (the footers required by the other settings) in place of the parts of the message
+	Template string `json:"template"`
+
+	// Issue references that every commit must contain
+	IssueReference IssueReferenceConfig `json:"issueReference"`
+
+	// URL of an issue, with "{issue}" in place of the reference without the "#" (e.g. "PROJ-42"),
+	// or "{number}" in place of the number of "#123" references. Derived from the "origin" remote
+	// if empty
+	IssueURL string `json:"issueUrl"`
+
+	// URL of a commit, with "{sha}" in place of the full commit hash. Derived from the "origin"
+	// remote if empty
+	CommitURL string `json:"commitUrl"`
+}
+
+// A commit type, written in JSON as either a string ("feat") or an object
+// ({"name": "feat", "description": "A new feature"})
+type CommitType struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (self *CommitType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*self = CommitType{Name: name}
+		return nil
+	}
+
+	type commitType CommitType
+	return json.Unmarshal(data, (*commitType)(self))
+}
+
+// The types used by @commitlint/config-conventional
+var DefaultTypes = []CommitType{
+	{Name: "feat", Description: "A new feature"},
+	{Name: "fix", Description: "A bug fix"},
+	{Name: "docs", Description: "Documentation only changes"},
+	{Name: "style", Description: "Changes that do not affect the meaning of the code (white-space, formatting, etc)"},
+	{Name: "refactor", Description: "A code change that neither fixes a bug nor adds a feature"},
+	{Name: "perf", Description: "A code change that improves performance"},
+	{Name: "test", Description: "Adding missing tests or correcting existing tests"},
+	{Name: "build", Description: "Changes that affect the build system or external dependencies"},
+	{Name: "ci", Description: "Changes to CI configuration files and scripts"},
+	{Name: "chore", Description: "Other changes that don't modify source or test files"},
+	{Name: "revert", Description: "Reverts a previous commit"},
+}
+
+const DefaultScopeDelimiters = ",/"
+
+// Files of a commit type
+type TypePattern struct {
+	Type string `json:"type"`
+	// Glob patterns (see path.Match) of the files. Patterns without a "/" match the name of the
+	// file in any directory, and patterns ending in "/**" match everything in a directory
+	Paths []string `json:"paths"`
+}
+
+var DefaultTypePatterns = []TypePattern{
+	{Type: "test", Paths: []string{"*_test.go", "*.test.*", "*.spec.*", "test_*.py", "test/**", "tests/**", "testdata/**", "__tests__/**"}},
+	{Type: "docs", Paths: []string{"*.md", "*.rst", "*.adoc", "docs/**", "doc/**", "LICENSE*"}},
+	{Type: "ci", Paths: []string{".github/workflows/**", ".gitlab-ci.yml", ".circleci/**", "Jenkinsfile", ".travis.yml"}},
+	{Type: "build", Paths: []string{"go.mod", "go.sum", "Makefile", "Dockerfile", "package.json", "package-lock.json", "Cargo.toml", "Cargo.lock"}},
+}
+
+// Whether the type is one of the configured types
+func (self *Config) HasType(name string) bool {
+	return slices.ContainsFunc(self.Types, func(t CommitType) bool {
+		return t.Name == name
+	})
+}
+
+const DefaultTemplate = "{type}({scope}): {description}\n\n{body}\n\n{footers}"
+
+// Values of breakingChange
+const (
+	BreakingChangeBang = "bang"
+	BreakingChangeBoth = "both"
+	BreakingChangeAny  = "any"
+)
+
+type TrailersConfig struct {
+	// Allowed keys. Any key is allowed if this is empty
+	Keys []string `json:"keys"`
+	// Keys that may only appear once
+	Unique []string `json:"unique"`
+	// Keys whose values must be "Name <email>"
+	Identity []string `json:"identity"`
+}
+
+type DescriptionConfig struct {
+	// Case of the first letter of the description: "lower" ("add x"), "sentence" ("Add x") or
+	// "any"
+	Case string `json:"case"`
+}
+
+// Cases of the first letter of the description
+const (
+	CaseLower    = "lower"
+	CaseSentence = "sentence"
+	CaseAny      = "any"
+)
+
+type IssueReferenceConfig struct {
+	// Whether every commit must reference an issue in the scope, the description or a "Refs" footer
+	Required bool `json:"required"`
+	// Regular expression that matches an issue reference (e.g. "PROJ-[0-9]+"). The ticket of the
+	// current branch is the first match in its name
+	Pattern string `json:"pattern"`
+}
+
+// "#123" or "PROJ-123". Keys have at least two characters, so that "X-1" is not an issue
+const DefaultIssuePattern = `#[0-9]+|[A-Z][A-Z0-9_]+-[0-9]+`
+
+// Names of standards and algorithms that look like issue keys (e.g. "UTF-8", "SHA-256")
+var NonIssueKeys = []string{
+	"AES", "CVE", "ECMA", "HTTP", "IEC", "IEEE", "ISO", "MD", "RFC", "RSA", "SHA", "UCS", "UTF",
+}
+
+// Whether a match of the issue pattern is an issue reference, and not a name like "UTF-8"
+func IsIssueReference(match string) bool {
+	key, _, ok := strings.Cut(match, "-")
+	return !ok || !slices.Contains(NonIssueKeys, key)
+}
+
+// The compiled issue reference pattern. Its matches should be checked with IsIssueReference
+func (self *Config) IssuePattern() *regexp.Regexp {
+	pattern, err := regexp.Compile(self.IssueReference.Pattern)
+	if err != nil || self.IssueReference.Pattern == "" {
+		return regexp.MustCompile(DefaultIssuePattern)
+	}
+	return pattern
+}
+
+// The issue references in the text, as pairs of indexes (see regexp.Regexp.FindAllStringIndex)
+func (self *Config) FindIssueReferences(text string) [][]int {
+	references := [][]int{}
+	for _, match := range self.IssuePattern().FindAllStringIndex(text, -1) {
+		if match[0] < match[1] && IsIssueReference(text[match[0]:match[1]]) {
+			references = append(references, match)
+		}
+	}
+	return references
+}
+
+// Whether the text contains an issue reference
+func (self *Config) HasIssueReference(text string) bool {
+	return len(self.FindIssueReferences(text)) > 0
+}
+
+// Trailers that git and common tools use for people
+var DefaultIdentityTrailers = []string{
+	"Signed-off-by",
+	"Co-authored-by",
+	"Reviewed-by",
+	"Acked-by",
+	"Tested-by",
+	"Reported-by",
+	"Suggested-by",
+	"Helped-by",
+}
+
+// Value of rules that disables a rule
+const RuleOff = "off"
+
+// The configured severity of a rule, or 0 if it keeps its own. False if the rule is disabled
+func (self *Config) RuleSeverity(id string) (lsp.DiagnosticSeverity, bool) {
+	value, ok := self.Rules[id]
+	if !ok {
+		return 0, true
+	} else if value == RuleOff {
+		return 0, false
+	}
+
+	severity, err := lsp.ParseDiagnosticSeverity(value)
+	if err != nil {
+		return 0, true
+	}
+	return severity, true
+}
+
+// A rule implemented by an external command
+//
+// The command receives the commit message on stdin, and writes the diagnostics to stdout.
+// See the external package for the format
+type ExternalRule struct {
+	// ID of the rule (e.g. "team/ticket-exists")
+	ID string `json:"id"`
+	// Short, human readable description of what the rule checks
+	Description string `json:"description"`
+	// Command and arguments to run. It is run in the root of the repository
+	Command []string `json:"command"`
+	// How long the command may run before it is killed (e.g. "500ms", "2s")
+	Timeout Duration `json:"timeout"`
+	// Default severity of the diagnostics ("error", "warning", "information" or "hint")
+	Severity string `json:"severity"`
+}
+
+const DefaultExternalRuleTimeout = 5 * time.Second
+
+// A time.Duration that is written as a string in JSON (e.g. "2s")
+type Duration time.Duration
+
+func (self *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*self = Duration(duration)
+	return nil
+}
+
+func (self Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(self).String())
+}
+
+func Default() *Config {
+	return &Config{
+		Types:            slices.Clone(DefaultTypes),
+		Scopes:           []string{},
+		ScopeDelimiters:  DefaultScopeDelimiters,
+		DeprecatedScopes: []string{},
+		ScopePaths:       map[string][]string{},
+		TypePatterns:     slices.Clone(DefaultTypePatterns),
+		ExternalRules:    []ExternalRule{},
+		Rules:            map[string]string{},
+		Trailers: TrailersConfig{
+			Keys:     []string{},
+			Unique:   []string{},
+			Identity: slices.Clone(DefaultIdentityTrailers),
+		},
+		BreakingChange: BreakingChangeBang,
+		Template:       DefaultTemplate,
+		Description:    DescriptionConfig{Case: CaseLower},
+		IssueReference: IssueReferenceConfig{Pattern: DefaultIssuePattern},
+	}
+}
+
+// Load the configuration file from the root of the repository
+//
+// If root is "" or the file does not exist, the default configuration is returned
+func Load(root string) (*Config, error) {
+	config := Default()
+	if root == "" {
+		return config, nil
+	}
+
+	path := filepath.Join(root, FileName)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	} else if err != nil {
+		return config, err
+	}
+
+	// Decode into an empty config, so that lists in the file replace the defaults instead of being
+	// merged with them
+	config = &Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return Default(), fmt.Errorf("%s: %w", path, err)
+	}
+	config.setDefaults()
+
+	if err := config.validate(); err != nil {
+		return Default(), fmt.Errorf("%s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// Use the default for every setting that is not in the file
+func (self *Config) setDefaults() {
+	defaults := Default()
+
+	if self.Types == nil {
+		self.Types = defaults.Types
+	}
+	if self.Scopes == nil {
+		self.Scopes = defaults.Scopes
+	}
+	if self.ScopeDelimiters == "" {
+		self.ScopeDelimiters = defaults.ScopeDelimiters
+	}
+	if self.DeprecatedScopes == nil {
+		self.DeprecatedScopes = defaults.DeprecatedScopes
+	}
+	if self.ScopePaths == nil {
+		self.ScopePaths = defaults.ScopePaths
+	}
+	if self.TypePatterns == nil {
+		self.TypePatterns = defaults.TypePatterns
+	}
+	if self.ExternalRules == nil {
+		self.ExternalRules = defaults.ExternalRules
+	}
+	if self.Rules == nil {
+		self.Rules = defaults.Rules
+	}
+	if self.Trailers.Keys == nil {
+		self.Trailers.Keys = defaults.Trailers.Keys
+	}
+	if self.Trailers.Unique == nil {
+		self.Trailers.Unique = defaults.Trailers.Unique
+	}
+	if self.Trailers.Identity == nil {
+		self.Trailers.Identity = defaults.Trailers.Identity
+	}
+	if self.Template == "" {
+		self.Template = defaults.Template
+	}
+	if self.BreakingChange == "" {
+		self.BreakingChange = defaults.BreakingChange
+	}
+	if self.Description.Case == "" {
+		self.Description.Case = defaults.Description.Case
+	}
+	if self.IssueReference.Pattern == "" {
+		self.IssueReference.Pattern = defaults.IssueReference.Pattern
+	}
+}
+
+func (self *Config) validate() error {
+	for i, commitType := range self.Types {
+		if commitType.Name == "" {
+			return fmt.Errorf("types[%d]: missing name", i)
+		}
+	}
+
+	for scope, paths := range self.ScopePaths {
+		for _, path := range paths {
+			if filepath.IsAbs(path) || !filepath.IsLocal(filepath.FromSlash(path)) {
+				return fmt.Errorf("scopePaths[%q]: %q is not inside the repository", scope, path)
+			}
+		}
+	}
+
+	for i, pattern := range self.TypePatterns {
+		if pattern.Type == "" {
+			return fmt.Errorf("typePatterns[%d]: missing type", i)
+		}
+		for _, p := range pattern.Paths {
+			if _, err := path.Match(p, ""); err != nil {
+				return fmt.Errorf("typePatterns[%d] (%s): %q: %w", i, pattern.Type, p, err)
+			}
+		}
+	}
+
+	if strings.ContainsAny(self.ScopeDelimiters, "():! \t") {
+		return errors.New("scopeDelimiters: must not contain parentheses, ':', '!' or whitespace")
+	}
+
+	if !slices.Contains([]string{BreakingChangeBang, BreakingChangeBoth, BreakingChangeAny}, self.BreakingChange) {
+		return fmt.Errorf("breakingChange: must be %q, %q or %q", BreakingChangeBang, BreakingChangeBoth, BreakingChangeAny)
+	}
+
+	if !slices.Contains([]string{CaseLower, CaseSentence, CaseAny}, self.Description.Case) {
+		return fmt.Errorf("description.case: must be %q, %q or %q", CaseLower, CaseSentence, CaseAny)
+	}
+
+	if _, err := regexp.Compile(self.IssueReference.Pattern); err != nil {
+		return fmt.Errorf("issueReference.pattern: %w", err)
+	}
+
+	if self.IssueURL != "" && !strings.Contains(self.IssueURL, "{issue}") && !strings.Contains(self.IssueURL, "{number}") {
+		return errors.New("issueUrl: must contain {issue} or {number}")
+	}
+	if self.CommitURL != "" && !strings.Contains(self.CommitURL, "{sha}") {
+		return errors.New("commitUrl: must contain {sha}")
+	}
+
+	for id, value := range self.Rules {
+		if value == RuleOff {
+			continue
+		}
+		if _, err := lsp.ParseDiagnosticSeverity(value); err != nil {
+			return fmt.Errorf("rules[%q]: %w (or %q)", id, err, RuleOff)
+		}
+	}
+
+	for i := range self.ExternalRules {
+		rule := &self.ExternalRules[i]
+		if rule.ID == "" {
+			return fmt.Errorf("externalRules[%d]: missing id", i)
+		}
+		if len(rule.Command) == 0 {
+			return fmt.Errorf("externalRules[%d] (%s): missing command", i, rule.ID)
+		}
+		if rule.Severity == "" {
+			rule.Severity = "warning"
+		} else if _, err := lsp.ParseDiagnosticSeverity(rule.Severity); err != nil {
+			return fmt.Errorf("externalRules[%d] (%s): %w", i, rule.ID, err)
+		}
+		if rule.Timeout <= 0 {
+			rule.Timeout = Duration(DefaultExternalRuleTimeout)
+		}
+	}
+
+	return nil
+}
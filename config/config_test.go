@@ -0,0 +1,136 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, FileName), []byte(content), 0o644))
+	return root
+}
+
+func TestLoad(t *testing.T) {
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, Default(), config)
+
+	config, err = Load(t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, Default(), config)
+
+	config, err = Load(writeConfig(t, `{"externalRules": [{"id": "team/a", "command": ["a"]}, {"id": "team/b", "command": ["b", "c"], "timeout": "250ms", "severity": "error"}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, []ExternalRule{
+		{ID: "team/a", Command: []string{"a"}, Timeout: Duration(DefaultExternalRuleTimeout), Severity: "warning"},
+		{ID: "team/b", Command: []string{"b", "c"}, Timeout: Duration(250 * time.Millisecond), Severity: "error"},
+	}, config.ExternalRules)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	_, err := Load(writeConfig(t, `{"externalRules": [{"id": "team/a"}]}`))
+	assert.ErrorContains(t, err, "missing command")
+
+	_, err = Load(writeConfig(t, `{"externalRules": [{"id": "team/a", "command": ["a"], "severity": "fatal"}]}`))
+	assert.ErrorContains(t, err, "unknown severity")
+
+	_, err = Load(writeConfig(t, `{"externalRules": [{"id": "team/a", "command": ["a"], "timeout": "soon"}]}`))
+	assert.Error(t, err)
+
+	_, err = Load(writeConfig(t, `{"scopePaths": {"api": ["../api"]}}`))
+	assert.ErrorContains(t, err, "scopePaths")
+
+	_, err = Load(writeConfig(t, `{"typePatterns": [{"type": "test", "paths": ["[a-"]}]}`))
+	assert.ErrorContains(t, err, "typePatterns[0] (test)")
+
+	_, err = Load(writeConfig(t, `{"scopeDelimiters": ", "}`))
+	assert.ErrorContains(t, err, "scopeDelimiters")
+
+	_, err = Load(writeConfig(t, `{"breakingChange": "footer"}`))
+	assert.ErrorContains(t, err, "breakingChange")
+
+	_, err = Load(writeConfig(t, `{"description": {"case": "upper"}}`))
+	assert.ErrorContains(t, err, "description.case")
+
+	_, err = Load(writeConfig(t, `{"issueReference": {"pattern": "PROJ-[0-9"}}`))
+	assert.ErrorContains(t, err, "issueReference.pattern")
+
+	_, err = Load(writeConfig(t, `{"issueUrl": "https://jira.example.com/browse/"}`))
+	assert.ErrorContains(t, err, "issueUrl")
+
+	_, err = Load(writeConfig(t, `{"commitUrl": "https://example.com/commit/{hash}"}`))
+	assert.ErrorContains(t, err, "commitUrl")
+
+	_, err = Load(writeConfig(t, `{"rules": {"header/empty-scope": "disabled"}}`))
+	assert.ErrorContains(t, err, `rules["header/empty-scope"]`)
+}
+
+func TestLoadTypes(t *testing.T) {
+	config, err := Load(writeConfig(t, `{"types": ["feat", {"name": "fix", "description": "A bug fix"}], "scopes": ["api"], "deprecatedScopes": ["old"]}`))
+	require.NoError(t, err)
+	assert.Equal(t, []CommitType{
+		{Name: "feat"},
+		{Name: "fix", Description: "A bug fix"},
+	}, config.Types)
+	assert.Equal(t, []string{"api"}, config.Scopes)
+	assert.Equal(t, []string{"old"}, config.DeprecatedScopes)
+	assert.True(t, config.HasType("fix"))
+	assert.False(t, config.HasType("docs"))
+
+	_, err = Load(writeConfig(t, `{"types": [{"description": "No name"}]}`))
+	assert.ErrorContains(t, err, "missing name")
+}
+
+func TestLoadTrailers(t *testing.T) {
+	config, err := Load(writeConfig(t, `{"trailers": {"unique": ["Change-Id"]}}`))
+	require.NoError(t, err)
+	assert.Equal(t, TrailersConfig{
+		Keys:     []string{},
+		Unique:   []string{"Change-Id"},
+		Identity: DefaultIdentityTrailers,
+	}, config.Trailers)
+}
+
+func TestLoadIssueReference(t *testing.T) {
+	config, err := Load(writeConfig(t, `{"issueReference": {"required": true}}`))
+	require.NoError(t, err)
+	assert.Equal(t, IssueReferenceConfig{Required: true, Pattern: DefaultIssuePattern}, config.IssueReference)
+	assert.Equal(t, "PROJ-7", config.IssuePattern().FindString("feature/PROJ-7-foo"))
+
+	config, err = Load(writeConfig(t, `{"issueReference": {"pattern": "ABC-[0-9]+"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "", config.IssuePattern().FindString("feature/PROJ-7-foo"))
+}
+
+func TestFindIssueReferences(t *testing.T) {
+	config := Default()
+	assert.Equal(t, [][]int{{0, 6}, {27, 29}}, config.FindIssueReferences("PROJ-1 UTF-8 SHA-256 X-1 a #3"))
+	assert.False(t, config.HasIssueReference("handle UTF-8 names"))
+	assert.False(t, config.HasIssueReference("use SHA-256 and ISO-8601"))
+	assert.True(t, config.HasIssueReference("fix AB-12"))
+}
+
+func TestLoadRules(t *testing.T) {
+	config, err := Load(writeConfig(t, `{"rules": {"description/imperative": "hint", "trailer/unknown-key": "off"}}`))
+	require.NoError(t, err)
+
+	severity, enabled := config.RuleSeverity("description/imperative")
+	assert.True(t, enabled)
+	assert.Equal(t, lsp.DiagnosticSeverityHint, severity)
+
+	_, enabled = config.RuleSeverity("trailer/unknown-key")
+	assert.False(t, enabled)
+
+	severity, enabled = config.RuleSeverity("header/empty-scope")
+	assert.True(t, enabled)
+	assert.Zero(t, severity)
+}
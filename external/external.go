@@ -0,0 +1,157 @@
+// Package external runs rules that are implemented by external commands
+//
+// The command receives an Input as JSON on stdin, and must write a JSON array of Output objects to
+// stdout (an empty array if there are no problems). For example:
+//
+//	[{"message": "PROJ-42 does not exist", "range": {"start": {"line": 0, "character": 5}, "end": {"line": 0, "character": 12}}}]
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// Written to the stdin of the command
+type Input struct {
+	// The full text of the commit message, including comments
+	Message string `json:"message"`
+	// The parsed commit message
+	Commit commit.Commit `json:"commit"`
+}
+
+// A diagnostic reported by the command
+type Output struct {
+	// Optional, defaults to the ID of the external rule. It may only be the ID of the external
+	// rule or an ID under it (e.g. "team/ticket/closed" for "team/ticket"), other IDs are replaced
+	// with the ID of the external rule
+	Rule string `json:"rule"`
+	// Required
+	Message string `json:"message"`
+	// Optional, defaults to the severity of the external rule
+	Severity string `json:"severity"`
+	// Optional, defaults to the header line
+	Range *lsp.Range `json:"range"`
+}
+
+// The IDs of the rules, so that they can be disabled by suppression comments
+func RuleIDs(rules []config.ExternalRule) []commit.DiagnosticType {
+	ids := make([]commit.DiagnosticType, len(rules))
+	for i, rule := range rules {
+		ids[i] = commit.DiagnosticType(rule.ID)
+	}
+
+	return ids
+}
+
+// Run a single external rule on a commit message
+func Run(rule config.ExternalRule, root string, text string, msg *commit.Message) ([]commit.Diagnostic, error) {
+	input, err := json.Marshal(Input{
+		Message: text,
+		Commit:  msg.Commit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(rule.Timeout))
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, rule.Command[0], rule.Command[1:]...)
+	cmd.Dir = root
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	runErr := cmd.Run()
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("timed out after %s", time.Duration(rule.Timeout))
+	}
+
+	var outputs []Output
+	if err := json.Unmarshal(stdout.Bytes(), &outputs); err != nil {
+		if runErr != nil {
+			return nil, fmt.Errorf("%w: %s", runErr, strings.TrimSpace(stderr.String()))
+		}
+		return nil, fmt.Errorf("invalid output: %w", err)
+	}
+
+	defaultSeverity, err := lsp.ParseDiagnosticSeverity(rule.Severity)
+	if err != nil {
+		return nil, err
+	}
+
+	diagnostics := []commit.Diagnostic{}
+	for _, output := range outputs {
+		diagnostic := commit.Diagnostic{
+			Range:    helper.LineRange(0, 0, len(msg.Header.Text)),
+			Type:     commit.DiagnosticType(rule.ID),
+			Message:  output.Message,
+			Severity: defaultSeverity,
+		}
+		if output.Rule == rule.ID || strings.HasPrefix(output.Rule, rule.ID+"/") {
+			// Built-in rules format their messages from Args, which external rules don't have
+			diagnostic.Type = commit.DiagnosticType(output.Rule)
+		}
+		if output.Range != nil {
+			diagnostic.Range = *output.Range
+		}
+		if output.Severity != "" {
+			severity, err := lsp.ParseDiagnosticSeverity(output.Severity)
+			if err != nil {
+				return nil, err
+			}
+			diagnostic.Severity = severity
+		}
+
+		diagnostics = append(diagnostics, diagnostic)
+	}
+
+	return diagnostics, nil
+}
+
+// Run all external rules concurrently, and merge their diagnostics
+//
+// A rule that fails is reported as a warning on the header line, so that a broken script does not
+// silently stop checking
+func RunAll(rules []config.ExternalRule, root string, text string, msg *commit.Message) []commit.Diagnostic {
+	results := make([][]commit.Diagnostic, len(rules))
+
+	var wg sync.WaitGroup
+	for i, rule := range rules {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			diagnostics, err := Run(rule, root, text, msg)
+			if err != nil {
+				diagnostics = []commit.Diagnostic{{
+					Range:    helper.LineRange(0, 0, len(msg.Header.Text)),
+					Type:     commit.DiagnosticType(rule.ID),
+					Message:  fmt.Sprintf("External rule '%s' failed: %v", rule.ID, err),
+					Severity: lsp.DiagnosticSeverityWarning,
+				}}
+			}
+			results[i] = diagnostics
+		}()
+	}
+	wg.Wait()
+
+	diagnostics := []commit.Diagnostic{}
+	for _, result := range results {
+		diagnostics = append(diagnostics, result...)
+	}
+
+	return diagnostics
+}
@@ -0,0 +1,92 @@
+package external
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeScript(t *testing.T, script string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "rule.sh")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755))
+	return path
+}
+
+func TestRun(t *testing.T) {
+	script := writeScript(t, `grep -q '"type":"feat"' && echo '[{"message": "bad", "range": {"start": {"line": 0, "character": 0}, "end": {"line": 0, "character": 4}}}, {"rule": "team/rule/other", "message": "worse", "severity": "error"}, {"rule": "header/extra-characters-after-scope", "message": "not mine"}]'`)
+	rule := config.ExternalRule{
+		ID:       "team/rule",
+		Command:  []string{script},
+		Timeout:  config.Duration(5 * time.Second),
+		Severity: "warning",
+	}
+
+	text := "feat: description"
+	diagnostics, err := Run(rule, "", text, commit.ParseMessage(text))
+	require.NoError(t, err)
+	assert.Equal(t, []commit.Diagnostic{
+		{
+			Range:    helper.LineRange(0, 0, 4),
+			Type:     "team/rule",
+			Message:  "bad",
+			Severity: lsp.DiagnosticSeverityWarning,
+		},
+		{
+			Range:    helper.LineRange(0, 0, len(text)),
+			Type:     "team/rule/other",
+			Message:  "worse",
+			Severity: lsp.DiagnosticSeverityError,
+		},
+		{
+			Range:    helper.LineRange(0, 0, len(text)),
+			Type:     "team/rule",
+			Message:  "not mine",
+			Severity: lsp.DiagnosticSeverityWarning,
+		},
+	}, diagnostics)
+
+	// The message of the script is used, not the message of the rule
+	assert.Equal(t, "not mine", diagnostics[2].ToLspDiagnostic().Message)
+}
+
+func TestRunErrors(t *testing.T) {
+	text := "feat: description"
+	msg := commit.ParseMessage(text)
+
+	_, err := Run(config.ExternalRule{
+		ID:       "team/slow",
+		Command:  []string{"sleep", "5"},
+		Timeout:  config.Duration(50 * time.Millisecond),
+		Severity: "warning",
+	}, "", text, msg)
+	assert.ErrorContains(t, err, "timed out")
+
+	_, err = Run(config.ExternalRule{
+		ID:       "team/invalid",
+		Command:  []string{writeScript(t, "echo not json")},
+		Timeout:  config.Duration(5 * time.Second),
+		Severity: "warning",
+	}, "", text, msg)
+	assert.ErrorContains(t, err, "invalid output")
+
+	diagnostics := RunAll([]config.ExternalRule{{
+		ID:       "team/failing",
+		Command:  []string{writeScript(t, "echo oops >&2; exit 1")},
+		Timeout:  config.Duration(5 * time.Second),
+		Severity: "error",
+	}}, "", text, msg)
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, commit.DiagnosticType("team/failing"), diagnostics[0].Type)
+	assert.Equal(t, lsp.DiagnosticSeverityWarning, diagnostics[0].Severity)
+	assert.Contains(t, diagnostics[0].Message, "oops")
+}
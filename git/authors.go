@@ -0,0 +1,87 @@
+package git
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// An author in the history of a repository
+type Author struct {
+	Identity
+	// Number of commits by the author
+	Commits int
+	// Index of the most recent commit by the author (0 is the newest commit)
+	Latest int
+}
+
+// The authors of the most recent commits that touched any of the paths (or of all commits if
+// there are no paths), most active first
+//
+// Names and emails are mapped with .mailmap, so each person is only returned once
+func Authors(root string, paths []string, max int) ([]Author, error) {
+	args := []string{"log", "--format=%aN%x00%aE", fmt.Sprintf("--max-count=%d", max), "HEAD", "--"}
+	output, err := Run(root, append(args, paths...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	authors := []Author{}
+	indexes := map[string]int{}
+	for i, line := range strings.Split(output, "\n") {
+		name, email, ok := strings.Cut(line, "\x00")
+		if !ok {
+			continue
+		}
+
+		key := strings.ToLower(email)
+		if index, ok := indexes[key]; ok {
+			authors[index].Commits++
+			continue
+		}
+		indexes[key] = len(authors)
+		authors = append(authors, Author{
+			Identity: Identity{Name: name, Email: email},
+			Commits:  1,
+			Latest:   i,
+		})
+	}
+
+	slices.SortStableFunc(authors, func(a, b Author) int {
+		if a.Commits != b.Commits {
+			return b.Commits - a.Commits
+		}
+		return a.Latest - b.Latest
+	})
+
+	return authors, nil
+}
+
+// Map an identity with .mailmap, like git does for the authors in the log
+func CheckMailmap(root string, identity Identity) Identity {
+	output, err := Run(root, "check-mailmap", identity.String())
+	if err != nil {
+		return identity
+	}
+
+	if mapped, ok := ParseIdentity(output); ok {
+		return mapped
+	}
+	return identity
+}
+
+// Paths of the files in the index that are different from HEAD
+func StagedFiles(root string) ([]string, error) {
+	output, err := Run(root, "diff", "--cached", "--name-only", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	for _, file := range strings.Split(output, "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
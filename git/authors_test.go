@@ -0,0 +1,44 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eamonburns/git-lsp/internal/testrepo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthors(t *testing.T) {
+	root, _ := newRepo(t)
+	commit := func(name, email, file string) {
+		testrepo.WriteFile(t, root, file, name)
+		testrepo.CommitAs(t, root, name, email, "x")
+	}
+	commit("Ann", "ann@example.com", "a.go")
+	commit("Bob", "bob@example.com", "b.go")
+	commit("Ann", "ANN@example.com", "b.go")
+
+	authors, err := Authors(root, nil, 100)
+	require.NoError(t, err)
+	assert.Equal(t, []Author{
+		{Identity: Identity{Name: "Ann", Email: "ANN@example.com"}, Commits: 2, Latest: 0},
+		{Identity: Identity{Name: "Bob", Email: "bob@example.com"}, Commits: 1, Latest: 1},
+		{Identity: Identity{Name: "Test", Email: "test@example.com"}, Commits: 1, Latest: 3},
+	}, authors)
+
+	authors, err = Authors(root, []string{"a.go"}, 100)
+	require.NoError(t, err)
+	assert.Equal(t, []Author{{Identity: Identity{Name: "Ann", Email: "ann@example.com"}, Commits: 1, Latest: 0}}, authors)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".mailmap"), []byte("Bob Smith <bob@smith.dev> <bob@example.com>\n"), 0o644))
+	assert.Equal(t, Identity{Name: "Bob Smith", Email: "bob@smith.dev"}, CheckMailmap(root, Identity{Name: "Bob", Email: "bob@example.com"}))
+	assert.Equal(t, Identity{Name: "Eve", Email: "eve@example.com"}, CheckMailmap(root, Identity{Name: "Eve", Email: "eve@example.com"}))
+
+	_, err = Run(root, "add", ".mailmap")
+	require.NoError(t, err)
+	staged, err := StagedFiles(root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{".mailmap"}, staged)
+}
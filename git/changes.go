@@ -0,0 +1,59 @@
+package git
+
+import (
+	"strings"
+)
+
+// A file in the index that is different from HEAD
+type Change struct {
+	// First letter of the status (e.g. 'A' for added, 'M' for modified, 'R' for renamed)
+	Status byte
+	Path   string
+	// Path before a rename or copy, or "" if the file was not renamed or copied
+	OldPath string
+}
+
+// Description of the status, in the words of `git status`
+func (self Change) StatusText() string {
+	switch self.Status {
+	case 'A':
+		return "new file"
+	case 'D':
+		return "deleted"
+	case 'R':
+		return "renamed"
+	case 'C':
+		return "copied"
+	case 'T':
+		return "typechange"
+	default:
+		return "modified"
+	}
+}
+
+// The staged changes, with the status of each file
+func StagedChanges(root string) ([]Change, error) {
+	output, err := Run(root, "diff", "--cached", "--name-status", "-z", "--find-renames")
+	if err != nil {
+		return nil, err
+	}
+
+	// Each change is the status followed by one path, or two for renames and copies
+	fields := strings.Split(output, "\x00")
+	changes := []Change{}
+	for i := 0; i+1 < len(fields); i += 2 {
+		status := fields[i]
+		if status == "" {
+			break
+		}
+
+		change := Change{Status: status[0], Path: fields[i+1]}
+		if (change.Status == 'R' || change.Status == 'C') && i+2 < len(fields) {
+			change.OldPath = change.Path
+			change.Path = fields[i+2]
+			i++
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
@@ -0,0 +1,30 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/eamonburns/git-lsp/internal/testrepo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStagedChanges(t *testing.T) {
+	root, _ := newRepo(t)
+
+	testrepo.WriteFile(t, root, "old.txt", "some content\nthat is long enough\nto be found as a rename\n")
+	testrepo.WriteFile(t, root, "removed.txt", "removed\n")
+	testrepo.Commit(t, root, "Add files")
+	testrepo.Git(t, root, "mv", "old.txt", "new.txt")
+	testrepo.Git(t, root, "rm", "--quiet", "removed.txt")
+	testrepo.WriteFile(t, root, "added.txt", "added\n")
+	testrepo.Git(t, root, "add", "added.txt")
+
+	changes, err := StagedChanges(root)
+	require.NoError(t, err)
+	assert.Equal(t, []Change{
+		{Status: 'A', Path: "added.txt"},
+		{Status: 'R', Path: "new.txt", OldPath: "old.txt"},
+		{Status: 'D', Path: "removed.txt"},
+	}, changes)
+	assert.Equal(t, "renamed", changes[1].StatusText())
+}
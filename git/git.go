@@ -0,0 +1,189 @@
+// Package git runs git commands in a repository
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Run git in the root of the repository, and return its output without the trailing newline
+//
+// If root is "", git is run in the current directory
+func Run(root string, args ...string) (string, error) {
+	command := args[0]
+	if root != "" {
+		args = append([]string{"-C", root}, args...)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("git %s: %w: %s", command, err, message)
+		}
+		return "", fmt.Errorf("git %s: %w", command, err)
+	}
+
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
+
+// The URL of a remote (e.g. "origin")
+func RemoteURL(root string, name string) (string, error) {
+	return Run(root, "remote", "get-url", name)
+}
+
+// The full hash of a commit, or false if the revision (e.g. an abbreviated hash) does not name
+// exactly one commit in the repository
+func RevParse(root string, rev string) (string, bool) {
+	hash, err := Run(root, "rev-parse", "--verify", "--quiet", "--end-of-options", rev+"^{commit}")
+	if err != nil {
+		return "", false
+	}
+	return hash, true
+}
+
+// The output of `git show` for a commit: the commit message, followed by the diff
+func Show(root string, rev string) (string, error) {
+	return Run(root, "show", "--no-color", "--no-ext-diff", "--end-of-options", rev)
+}
+
+// Lines added and deleted in a file
+type FileStat struct {
+	Path      string
+	Additions int
+	Deletions int
+	// Binary files have no lines
+	Binary bool
+}
+
+// The files changed by a commit, compared to its first parent
+func ShowStats(root string, rev string) ([]FileStat, error) {
+	output, err := Run(root, "show", "--numstat", "--format=", "--first-parent", "--no-color", "--no-ext-diff", "--end-of-options", rev)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := []FileStat{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+
+		stat := FileStat{Path: fields[2], Binary: fields[0] == "-"}
+		stat.Additions, _ = strconv.Atoi(fields[0])
+		stat.Deletions, _ = strconv.Atoi(fields[1])
+		stats = append(stats, stat)
+	}
+	return stats, nil
+}
+
+// The output of `git blame` for a file, at a revision or in the working tree if rev is ""
+func Blame(root string, rev string, path string) (string, error) {
+	args := []string{"blame"}
+	if rev != "" {
+		args = append(args, "--end-of-options", rev)
+	}
+	return Run(root, append(args, "--", path)...)
+}
+
+// A commit in the output of Log
+type LogEntry struct {
+	Hash    string
+	Subject string
+}
+
+// The most recent commits of the current branch, newest first
+func Log(root string, max int) ([]LogEntry, error) {
+	output, err := Run(root, "log", "--format=%H%x00%s", fmt.Sprintf("--max-count=%d", max), "HEAD", "--")
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []LogEntry{}
+	for _, line := range strings.Split(output, "\n") {
+		if hash, subject, ok := strings.Cut(line, "\x00"); ok {
+			entries = append(entries, LogEntry{Hash: hash, Subject: subject})
+		}
+	}
+
+	return entries, nil
+}
+
+// The value of a git config variable (e.g. "trailer.separators"), or false if it is not set
+func Config(root string, key string) (string, bool) {
+	value, err := Run(root, "config", "--get", key)
+	if err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+// The short name of the current branch (e.g. "feature/PROJ-123-foo"), or false if HEAD is
+// detached
+func Branch(root string) (string, bool) {
+	branch, err := Run(root, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		return "", false
+	}
+	return branch, true
+}
+
+// A person, as written in commits and trailers: "Name <email>"
+type Identity struct {
+	Name  string
+	Email string
+}
+
+func (self Identity) String() string {
+	return fmt.Sprintf("%s <%s>", self.Name, self.Email)
+}
+
+// The identity git uses for the committer of new commits, from the GIT_COMMITTER_NAME and
+// GIT_COMMITTER_EMAIL environment variables, or user.name and user.email in the git config
+//
+// Returns false if the name or email is not configured
+func Committer(root string) (Identity, bool) {
+	identity := Identity{
+		Name:  os.Getenv("GIT_COMMITTER_NAME"),
+		Email: os.Getenv("GIT_COMMITTER_EMAIL"),
+	}
+	if identity.Name == "" {
+		identity.Name, _ = Config(root, "user.name")
+	}
+	if identity.Email == "" {
+		identity.Email, _ = Config(root, "user.email")
+	}
+	if identity.Email == "" {
+		identity.Email = os.Getenv("EMAIL")
+	}
+
+	if identity.Name == "" || identity.Email == "" {
+		return Identity{}, false
+	}
+	return identity, true
+}
+
+var identityPattern = regexp.MustCompile(`^(.*?)\s*<([^<>\s]+)>$`)
+
+// Parse "Name <email>". Returns false if there is no email in angle brackets
+func ParseIdentity(s string) (Identity, bool) {
+	match := identityPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if match == nil {
+		return Identity{}, false
+	}
+	return Identity{Name: match[1], Email: match[2]}, true
+}
+
+// Whether two identities are the same person. Emails are case-insensitive
+func (self Identity) Matches(other Identity) bool {
+	return self.Name == other.Name && strings.EqualFold(self.Email, other.Email)
+}
@@ -0,0 +1,101 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/eamonburns/git-lsp/internal/testrepo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Create a repository with a single commit, and return its root and the hash of the commit
+func newRepo(t *testing.T) (string, string) {
+	t.Helper()
+
+	root := testrepo.Init(t)
+	hash := testrepo.Commit(t, root, "Initial commit")
+	testrepo.Git(t, root, "remote", "add", "origin", "git@github.com:owner/repo.git")
+	return root, hash
+}
+
+func TestRevParse(t *testing.T) {
+	root, hash := newRepo(t)
+
+	resolved, ok := RevParse(root, hash[:7])
+	assert.True(t, ok)
+	assert.Equal(t, hash, resolved)
+
+	_, ok = RevParse(root, "0000000")
+	assert.False(t, ok)
+	_, ok = RevParse(root, "--all")
+	assert.False(t, ok)
+}
+
+func TestShowStats(t *testing.T) {
+	root, _ := newRepo(t)
+	testrepo.WriteFile(t, root, "a.txt", "1\n2\n")
+	testrepo.WriteFile(t, root, "b.bin", "\x00\x01\x02")
+	testrepo.Commit(t, root, "Add files")
+
+	stats, err := ShowStats(root, "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, []FileStat{
+		{Path: "a.txt", Additions: 2},
+		{Path: "b.bin", Binary: true},
+	}, stats)
+}
+
+func TestRemoteURL(t *testing.T) {
+	root, _ := newRepo(t)
+
+	url, err := RemoteURL(root, "origin")
+	require.NoError(t, err)
+	assert.Equal(t, "git@github.com:owner/repo.git", url)
+
+	_, err = RemoteURL(root, "upstream")
+	assert.ErrorContains(t, err, "git remote")
+}
+
+func TestBranch(t *testing.T) {
+	root, hash := newRepo(t)
+
+	_, err := Run(root, "checkout", "--quiet", "-b", "feature/PROJ-123-foo")
+	require.NoError(t, err)
+	branch, ok := Branch(root)
+	assert.True(t, ok)
+	assert.Equal(t, "feature/PROJ-123-foo", branch)
+
+	_, err = Run(root, "checkout", "--quiet", "--detach", hash)
+	require.NoError(t, err)
+	_, ok = Branch(root)
+	assert.False(t, ok)
+}
+
+func TestCommitter(t *testing.T) {
+	root, _ := newRepo(t)
+	_, err := Run(root, "config", "user.name", "Repo User")
+	require.NoError(t, err)
+	_, err = Run(root, "config", "user.email", "repo@example.com")
+	require.NoError(t, err)
+
+	t.Setenv("GIT_COMMITTER_NAME", "")
+	t.Setenv("GIT_COMMITTER_EMAIL", "")
+	identity, ok := Committer(root)
+	assert.True(t, ok)
+	assert.Equal(t, Identity{Name: "Repo User", Email: "repo@example.com"}, identity)
+
+	t.Setenv("GIT_COMMITTER_EMAIL", "env@example.com")
+	identity, _ = Committer(root)
+	assert.Equal(t, "Repo User <env@example.com>", identity.String())
+}
+
+func TestParseIdentity(t *testing.T) {
+	identity, ok := ParseIdentity("Jo Doe <jo@example.com>")
+	assert.True(t, ok)
+	assert.Equal(t, Identity{Name: "Jo Doe", Email: "jo@example.com"}, identity)
+	assert.True(t, identity.Matches(Identity{Name: "Jo Doe", Email: "JO@example.com"}))
+	assert.False(t, identity.Matches(Identity{Name: "Jo", Email: "jo@example.com"}))
+
+	_, ok = ParseIdentity("Jo Doe")
+	assert.False(t, ok)
+}
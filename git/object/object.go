@@ -0,0 +1,174 @@
+// Package object reads objects (commits, trees, blobs and tags) from a git repository
+//
+// Objects are read through a long running `git cat-file --batch` process, so that reading many
+// objects does not start a process for each of them
+package object
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type Type string
+
+const (
+	TypeCommit Type = "commit"
+	TypeTree   Type = "tree"
+	TypeBlob   Type = "blob"
+	TypeTag    Type = "tag"
+)
+
+type Object struct {
+	// Full hash of the object
+	Hash string
+	Type Type
+	Data []byte
+}
+
+var ErrNotFound = errors.New("object not found")
+
+// Reads objects from a single repository. It is safe to use from multiple goroutines
+type Reader struct {
+	mutex  sync.Mutex
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+}
+
+// Start reading objects from the repository at root
+func NewReader(root string) (*Reader, error) {
+	cmd := exec.Command("git", "-C", root, "cat-file", "--batch")
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	return &Reader{
+		cmd:    cmd,
+		stdin:  stdin,
+		stdout: bufio.NewReader(stdout),
+	}, nil
+}
+
+// Read the object named by a revision (e.g. "HEAD", "1a2b3c4", "HEAD:README.md")
+//
+// Returns ErrNotFound if the revision does not name exactly one object
+func (self *Reader) Read(rev string) (*Object, error) {
+	if rev == "" || strings.ContainsAny(rev, "\n\r") {
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, rev)
+	}
+
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if _, err := io.WriteString(self.stdin, rev+"\n"); err != nil {
+		return nil, err
+	}
+
+	// "<hash> <type> <size>", or "<rev> missing" / "<rev> ambiguous"
+	header, err := self.stdout.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	header = strings.TrimSuffix(header, "\n")
+	if header == rev+" missing" || header == rev+" ambiguous" {
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, rev)
+	}
+
+	fields := strings.Fields(header)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("invalid object header %q", header)
+	}
+
+	size, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid object header %q", header)
+	}
+
+	// The content is followed by a newline
+	data := make([]byte, size+1)
+	if _, err := io.ReadFull(self.stdout, data); err != nil {
+		return nil, err
+	}
+
+	return &Object{
+		Hash: fields[0],
+		Type: Type(fields[1]),
+		Data: data[:size],
+	}, nil
+}
+
+// Stop the git process
+func (self *Reader) Close() error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	self.stdin.Close()
+	return self.cmd.Wait()
+}
+
+// A parsed commit object
+type Commit struct {
+	Tree    string
+	Parents []string
+	// "Name <email> timestamp timezone"
+	Author    string
+	Committer string
+	// The full commit message, including the trailing newline
+	Message string
+}
+
+// Parse the data of a commit object
+func ParseCommit(data []byte) (*Commit, error) {
+	headers, message, ok := strings.Cut(string(data), "\n\n")
+	if !ok {
+		// A commit with an empty message
+		headers = strings.TrimSuffix(string(data), "\n")
+	}
+
+	commit := &Commit{Message: message}
+	for _, line := range strings.Split(headers, "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "tree":
+			commit.Tree = value
+		case "parent":
+			commit.Parents = append(commit.Parents, value)
+		case "author":
+			commit.Author = value
+		case "committer":
+			commit.Committer = value
+		}
+		// Other headers (e.g. "gpgsig" and its continuation lines) are ignored
+	}
+
+	if commit.Tree == "" {
+		return nil, errors.New("invalid commit: missing tree")
+	}
+
+	return commit, nil
+}
+
+// Read and parse a commit
+func (self *Reader) ReadCommit(rev string) (*Commit, error) {
+	object, err := self.Read(rev + "^{commit}")
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseCommit(object.Data)
+}
@@ -0,0 +1,63 @@
+package object
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eamonburns/git-lsp/internal/testrepo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReader(t *testing.T) {
+	root := testrepo.Init(t)
+	testrepo.WriteFile(t, root, "README.md", "# Test\n")
+	testrepo.WriteFile(t, root, "release notes.txt", "Notes\n")
+	hash := testrepo.Commit(t, root, "feat: initial\n\nBody")
+
+	reader, err := NewReader(root)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	blob, err := reader.Read("HEAD:README.md")
+	require.NoError(t, err)
+	assert.Equal(t, TypeBlob, blob.Type)
+	assert.Equal(t, "# Test\n", string(blob.Data))
+
+	commit, err := reader.ReadCommit(hash[:7])
+	require.NoError(t, err)
+	assert.Equal(t, "feat: initial\n\nBody\n", commit.Message)
+	assert.Empty(t, commit.Parents)
+	assert.True(t, strings.HasPrefix(commit.Author, "Test <test@example.com> "))
+
+	_, err = reader.Read("0000000")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = reader.Read("HEAD\nHEAD")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Paths with spaces
+	blob, err = reader.Read("HEAD:release notes.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "Notes\n", string(blob.Data))
+	_, err = reader.Read("HEAD:missing file")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// The reader still works after an object was not found
+	_, err = reader.Read("HEAD")
+	assert.NoError(t, err)
+}
+
+func TestParseCommit(t *testing.T) {
+	commit, err := ParseCommit([]byte("tree 1234\nparent abcd\nparent ef01\nauthor A <a@b> 1 +0000\ncommitter C <c@d> 2 +0000\ngpgsig -----BEGIN-----\n data\n -----END-----\n\nfix: x\n"))
+	require.NoError(t, err)
+	assert.Equal(t, &Commit{
+		Tree:      "1234",
+		Parents:   []string{"abcd", "ef01"},
+		Author:    "A <a@b> 1 +0000",
+		Committer: "C <c@d> 2 +0000",
+		Message:   "fix: x\n",
+	}, commit)
+
+	_, err = ParseCommit([]byte("author A <a@b> 1 +0000\n\nfix: x\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,114 @@
+package git
+
+import (
+	"net/url"
+	"strings"
+)
+
+type Forge int
+
+const (
+	UnknownForge Forge = iota
+	GitHub
+	GitLab
+	Gitea
+)
+
+// A remote repository on a web host
+type Remote struct {
+	// "https" or "http"
+	Scheme string
+	// Host name, without the port
+	Host string
+	// Path of the repository, without the leading "/" and the ".git" suffix (e.g. "owner/repo")
+	Path string
+}
+
+// Parse the URL of a remote, which may be an HTTP(S) URL, an SSH URL, or an scp-like address
+// ("git@github.com:owner/repo.git")
+//
+// Returns false for local paths and URLs without a repository path
+func ParseRemote(remoteURL string) (Remote, bool) {
+	remote := Remote{Scheme: "https"}
+
+	if strings.Contains(remoteURL, "://") {
+		parsed, err := url.Parse(remoteURL)
+		if err != nil {
+			return Remote{}, false
+		}
+
+		switch parsed.Scheme {
+		case "http", "https":
+			remote.Scheme = parsed.Scheme
+		case "ssh", "git", "git+ssh", "ssh+git":
+		default:
+			return Remote{}, false
+		}
+		remote.Host = parsed.Hostname()
+		remote.Path = parsed.Path
+	} else {
+		// scp-like: "[user@]host:path". A local path can't contain ':' before the first '/'
+		host, path, ok := strings.Cut(remoteURL, ":")
+		if !ok || strings.Contains(host, "/") {
+			return Remote{}, false
+		}
+		if _, after, ok := strings.Cut(host, "@"); ok {
+			host = after
+		}
+		remote.Host = host
+		remote.Path = path
+	}
+
+	remote.Path = strings.TrimSuffix(strings.Trim(remote.Path, "/"), ".git")
+	if remote.Host == "" || remote.Path == "" {
+		return Remote{}, false
+	}
+
+	return remote, true
+}
+
+// The forge that hosts the remote, guessed from the host name
+func (self Remote) Forge() Forge {
+	host := strings.ToLower(self.Host)
+	switch {
+	case host == "github.com" || strings.HasPrefix(host, "github."):
+		return GitHub
+	case strings.Contains(host, "gitlab"):
+		return GitLab
+	case strings.Contains(host, "gitea") || strings.Contains(host, "forgejo") || host == "codeberg.org":
+		return Gitea
+	default:
+		return UnknownForge
+	}
+}
+
+// URL of the repository web page (e.g. "https://github.com/owner/repo")
+func (self Remote) WebURL() string {
+	return self.Scheme + "://" + self.Host + "/" + self.Path
+}
+
+// Template of the URL of an issue, with "{number}" in place of the issue number. Returns "" if the
+// forge is unknown
+func (self Remote) IssueURL() string {
+	switch self.Forge() {
+	case GitHub, Gitea:
+		return self.WebURL() + "/issues/{number}"
+	case GitLab:
+		return self.WebURL() + "/-/issues/{number}"
+	default:
+		return ""
+	}
+}
+
+// Template of the URL of a commit, with "{sha}" in place of the full commit hash. Returns "" if
+// the forge is unknown
+func (self Remote) CommitURL() string {
+	switch self.Forge() {
+	case GitHub, Gitea:
+		return self.WebURL() + "/commit/{sha}"
+	case GitLab:
+		return self.WebURL() + "/-/commit/{sha}"
+	default:
+		return ""
+	}
+}
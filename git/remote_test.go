@@ -0,0 +1,52 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRemote(t *testing.T) {
+	tests := []struct {
+		url    string
+		remote Remote
+		ok     bool
+	}{
+		{"https://github.com/owner/repo.git", Remote{"https", "github.com", "owner/repo"}, true},
+		{"http://gitea.example.com:3000/owner/repo/", Remote{"http", "gitea.example.com", "owner/repo"}, true},
+		{"ssh://git@gitlab.com:2222/group/sub/repo.git", Remote{"https", "gitlab.com", "group/sub/repo"}, true},
+		{"git@github.com:owner/repo.git", Remote{"https", "github.com", "owner/repo"}, true},
+		{"codeberg.org:owner/repo", Remote{"https", "codeberg.org", "owner/repo"}, true},
+		{"/srv/git/repo.git", Remote{}, false},
+		{"./repo", Remote{}, false},
+		{"file:///srv/git/repo.git", Remote{}, false},
+		{"https://github.com/", Remote{}, false},
+	}
+
+	for _, test := range tests {
+		remote, ok := ParseRemote(test.url)
+		assert.Equal(t, test.ok, ok, test.url)
+		assert.Equal(t, test.remote, remote, test.url)
+	}
+}
+
+func TestRemoteURLs(t *testing.T) {
+	remote, _ := ParseRemote("git@github.com:owner/repo.git")
+	assert.Equal(t, GitHub, remote.Forge())
+	assert.Equal(t, "https://github.com/owner/repo/issues/{number}", remote.IssueURL())
+	assert.Equal(t, "https://github.com/owner/repo/commit/{sha}", remote.CommitURL())
+
+	remote, _ = ParseRemote("https://gitlab.example.com/group/repo")
+	assert.Equal(t, GitLab, remote.Forge())
+	assert.Equal(t, "https://gitlab.example.com/group/repo/-/issues/{number}", remote.IssueURL())
+	assert.Equal(t, "https://gitlab.example.com/group/repo/-/commit/{sha}", remote.CommitURL())
+
+	remote, _ = ParseRemote("https://codeberg.org/owner/repo")
+	assert.Equal(t, Gitea, remote.Forge())
+	assert.Equal(t, "https://codeberg.org/owner/repo/commit/{sha}", remote.CommitURL())
+
+	remote, _ = ParseRemote("https://git.example.com/owner/repo")
+	assert.Equal(t, UnknownForge, remote.Forge())
+	assert.Equal(t, "", remote.IssueURL())
+	assert.Equal(t, "", remote.CommitURL())
+}
@@ -0,0 +1,18 @@
+package helper
+
+import "github.com/eamonburns/git-lsp/lsp"
+
+// Whether a is before b
+func PositionBefore(a lsp.Position, b lsp.Position) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Character < b.Character)
+}
+
+// Whether the ranges overlap (touching ranges are considered overlapping)
+func RangesOverlap(a lsp.Range, b lsp.Range) bool {
+	return !PositionBefore(a.End, b.Start) && !PositionBefore(b.End, a.Start)
+}
+
+// Whether the position is inside the range (inclusive)
+func RangeContains(r lsp.Range, position lsp.Position) bool {
+	return !PositionBefore(position, r.Start) && !PositionBefore(r.End, position)
+}
@@ -0,0 +1,32 @@
+package helper
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Find the root of the working tree that contains the file
+//
+// Commit messages are usually edited inside the git directory (e.g. ".git/COMMIT_EDITMSG"), so
+// the root is the parent of the ".git" directory. Returns "" if it can't be found
+func RepoRoot(path string) string {
+	if path == "" {
+		return ""
+	}
+
+	dir := filepath.Dir(path)
+	for {
+		if filepath.Base(dir) == ".git" {
+			return filepath.Dir(dir)
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,21 @@
+package helper
+
+import (
+	"net/url"
+	"path/filepath"
+)
+
+// Convert a "file://" URI to a file path. Returns "" if the URI is not a file URI
+func URIToPath(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme != "file" {
+		return ""
+	}
+
+	return filepath.FromSlash(parsed.Path)
+}
+
+// Convert a file path to a "file://" URI
+func PathToURI(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
@@ -0,0 +1,71 @@
+// Package testrepo creates git repositories for tests
+package testrepo
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Create a repository with an empty commit for each message, and return its root
+func New(t testing.TB, messages ...string) string {
+	t.Helper()
+
+	root := Init(t)
+	for _, message := range messages {
+		Commit(t, root, message)
+	}
+	return root
+}
+
+// Create a repository without commits, and return its root
+func Init(t testing.TB) string {
+	t.Helper()
+
+	root := t.TempDir()
+	Git(t, root, "init", "--quiet")
+	return root
+}
+
+// Run git in the repository, and return its output without the trailing newline
+func Git(t testing.TB, root string, args ...string) string {
+	t.Helper()
+
+	output, err := exec.Command("git", append([]string{"-C", root}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSuffix(string(output), "\n")
+}
+
+// Write a file in the working tree, creating its directory if needed
+func WriteFile(t testing.TB, root string, path string, content string) {
+	t.Helper()
+
+	path = filepath.Join(root, path)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Commit all changes in the working tree as "Test <test@example.com>", and return the hash of the
+// commit
+func Commit(t testing.TB, root string, message string) string {
+	t.Helper()
+
+	return CommitAs(t, root, "Test", "test@example.com", message)
+}
+
+// Commit all changes in the working tree as another author, and return the hash of the commit
+func CommitAs(t testing.TB, root string, name string, email string, message string) string {
+	t.Helper()
+
+	Git(t, root, "add", "--all")
+	Git(t, root, "-c", "user.name="+name, "-c", "user.email="+email, "commit", "--quiet", "--allow-empty", "-m", message)
+	return Git(t, root, "rev-parse", "HEAD")
+}
@@ -0,0 +1,151 @@
+package issue
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The issues of a repository, stored in the state directory
+//
+// The file is read again when it changes, so issues imported while the server is running are used
+// immediately
+type Index struct {
+	path string
+
+	mutex   sync.Mutex
+	modTime time.Time
+	issues  []Issue
+	// Index in issues, by upper case ID
+	ids map[string]int
+}
+
+type indexFile struct {
+	// Root of the repository, so that the file can be identified
+	Root   string  `json:"root"`
+	Issues []Issue `json:"issues"`
+}
+
+// The index of the repository with the given root. The file is not read until the index is used
+func OpenIndex(stateDir string, root string) *Index {
+	hash := sha256.Sum256([]byte(root))
+	return &Index{
+		path: filepath.Join(stateDir, "issues", hex.EncodeToString(hash[:8])+".json"),
+		ids:  map[string]int{},
+	}
+}
+
+// Read the file again if it changed since it was last read
+func (self *Index) load() {
+	info, err := os.Stat(self.path)
+	if err != nil {
+		self.issues, self.ids, self.modTime = nil, map[string]int{}, time.Time{}
+		return
+	}
+	if info.ModTime().Equal(self.modTime) {
+		return
+	}
+
+	data, err := os.ReadFile(self.path)
+	var file indexFile
+	if err == nil {
+		err = json.Unmarshal(data, &file)
+	}
+	if err != nil {
+		slog.Error("unable to read issue index", "path", self.path, "error", err)
+		return
+	}
+
+	self.modTime = info.ModTime()
+	self.issues = file.Issues
+	self.ids = map[string]int{}
+	for i, issue := range self.issues {
+		self.ids[strings.ToUpper(issue.ID)] = i
+	}
+}
+
+func (self *Index) Issue(id string) (Issue, bool) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	self.load()
+
+	i, ok := self.ids[strings.ToUpper(id)]
+	if !ok {
+		return Issue{}, false
+	}
+	return self.issues[i], true
+}
+
+func (self *Index) Search(query string, max int) []Issue {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	self.load()
+
+	query = strings.ToLower(query)
+	issues := []Issue{}
+	for _, issue := range self.issues {
+		if len(issues) == max {
+			break
+		}
+		if strings.Contains(strings.ToLower(issue.ID), query) || strings.Contains(strings.ToLower(issue.Title), query) {
+			issues = append(issues, issue)
+		}
+	}
+
+	return issues
+}
+
+// Add the issues to the index of the repository, replacing the issues with the same IDs
+//
+// Returns the number of issues in the index
+func Import(stateDir string, root string, issues []Issue) (int, error) {
+	index := OpenIndex(stateDir, root)
+
+	file := indexFile{Root: root}
+	if data, err := os.ReadFile(index.path); err == nil {
+		if err := json.Unmarshal(data, &file); err != nil {
+			return 0, err
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+
+	ids := map[string]int{}
+	for i, issue := range file.Issues {
+		ids[strings.ToUpper(issue.ID)] = i
+	}
+	for _, issue := range issues {
+		if i, ok := ids[strings.ToUpper(issue.ID)]; ok {
+			file.Issues[i] = issue
+			continue
+		}
+		ids[strings.ToUpper(issue.ID)] = len(file.Issues)
+		file.Issues = append(file.Issues, issue)
+	}
+
+	data, err := json.MarshalIndent(file, "", "\t")
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(index.path), 0o755); err != nil {
+		return 0, err
+	}
+
+	// Write to a temporary file first, so that the server never reads a partial index
+	tmp := index.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp, index.path); err != nil {
+		return 0, err
+	}
+
+	return len(file.Issues), nil
+}
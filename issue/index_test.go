@@ -0,0 +1,43 @@
+package issue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndex(t *testing.T) {
+	stateDir := t.TempDir()
+	index := OpenIndex(stateDir, "/repo")
+
+	_, ok := index.Issue("#1")
+	assert.False(t, ok)
+	assert.Empty(t, index.Search("", 10))
+
+	total, err := Import(stateDir, "/repo", []Issue{
+		{ID: "#1", Title: "Crash on start"},
+		{ID: "PROJ-2", Title: "Add login"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+
+	found, ok := index.Issue("proj-2")
+	assert.True(t, ok)
+	assert.Equal(t, Issue{ID: "PROJ-2", Title: "Add login"}, found)
+	assert.Equal(t, []Issue{{ID: "#1", Title: "Crash on start"}}, index.Search("CRASH", 10))
+	assert.Len(t, index.Search("", 1), 1)
+
+	// Issues with the same ID are replaced
+	total, err = Import(stateDir, "/repo", []Issue{{ID: "#1", Title: "Crash on exit", State: "closed"}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+
+	// Other repositories have their own index
+	_, ok = OpenIndex(stateDir, "/other").Issue("#1")
+	assert.False(t, ok)
+
+	found, ok = OpenIndex(stateDir, "/repo").Issue("#1")
+	assert.True(t, ok)
+	assert.Equal(t, Issue{ID: "#1", Title: "Crash on exit", State: "closed"}, found)
+}
@@ -0,0 +1,158 @@
+// Package issue looks up the issues that commit messages refer to (e.g. "#123", "PROJ-42")
+//
+// Editors must not wait for the network, so issues are read from a local index, which is filled
+// by importing an export of the issue tracker (see Import)
+package issue
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type Issue struct {
+	// Reference to the issue, as written in commit messages (e.g. "#123", "PROJ-42")
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	// e.g. "open", "closed", "In Progress"
+	State string `json:"state,omitempty"`
+	URL   string `json:"url,omitempty"`
+}
+
+// Source of issues. The index is the only provider for now, but providers that query the issue
+// tracker (e.g. over HTTP) can be added later
+type Provider interface {
+	// The issue with the reference (e.g. "#123", "PROJ-42"), or false if it is unknown
+	Issue(id string) (Issue, bool)
+	// At most max issues whose reference or title contains the query (case-insensitive)
+	Search(query string, max int) []Issue
+}
+
+// Names of the fields of an issue in exports of common issue trackers, in order of preference
+//
+// GitHub and GitLab exports also have a global "id", which is not the number used in references,
+// so it is only used if there is nothing else
+var fieldNames = map[string][]string{
+	"id":    {"number", "iid", "key", "issue key", "id"},
+	"title": {"title", "summary"},
+	"state": {"state", "status"},
+	"url":   {"url", "html_url", "web_url", "link"},
+}
+
+// Make an issue from the fields of an export, or return an error if it has no ID or title
+//
+// Numbers (e.g. "123" from GitHub) are referred to as "#123"
+func fromFields(fields map[string]string) (Issue, error) {
+	get := func(field string) string {
+		for _, name := range fieldNames[field] {
+			if value := strings.TrimSpace(fields[name]); value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+
+	issue := Issue{
+		ID:    get("id"),
+		Title: get("title"),
+		State: get("state"),
+		URL:   get("url"),
+	}
+	if issue.ID == "" {
+		return Issue{}, errors.New("missing id")
+	}
+	if _, err := strconv.Atoi(issue.ID); err == nil {
+		issue.ID = "#" + issue.ID
+	}
+	if issue.Title == "" {
+		return Issue{}, fmt.Errorf("%s: missing title", issue.ID)
+	}
+
+	return issue, nil
+}
+
+// Parse a JSON array of issues. Field names are case-insensitive, and values may be strings or
+// numbers (e.g. `[{"number": 123, "title": "Crash", "state": "open"}]`)
+func ParseJSON(reader io.Reader) ([]Issue, error) {
+	var objects []map[string]any
+	if err := json.NewDecoder(reader).Decode(&objects); err != nil {
+		return nil, err
+	}
+
+	issues := []Issue{}
+	for i, object := range objects {
+		fields := map[string]string{}
+		for name, value := range object {
+			switch value := value.(type) {
+			case string:
+				fields[strings.ToLower(name)] = value
+			case float64:
+				fields[strings.ToLower(name)] = strconv.FormatFloat(value, 'f', -1, 64)
+			}
+		}
+
+		issue, err := fromFields(fields)
+		if err != nil {
+			return nil, fmt.Errorf("issue %d: %w", i, err)
+		}
+		issues = append(issues, issue)
+	}
+
+	return issues, nil
+}
+
+// Parse CSV with a header row. Column names are case-insensitive (e.g. "Issue key,Summary,Status")
+func ParseCSV(reader io.Reader) ([]Issue, error) {
+	records, err := csv.NewReader(reader).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return []Issue{}, nil
+	}
+
+	header := records[0]
+	issues := []Issue{}
+	for i, record := range records[1:] {
+		fields := map[string]string{}
+		for column, value := range record {
+			fields[strings.ToLower(strings.TrimSpace(header[column]))] = value
+		}
+
+		issue, err := fromFields(fields)
+		if err != nil {
+			// Line 1 is the header
+			return nil, fmt.Errorf("line %d: %w", i+2, err)
+		}
+		issues = append(issues, issue)
+	}
+
+	return issues, nil
+}
+
+// Read the issues from a ".csv" or JSON file
+func ReadFile(path string) ([]Issue, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var issues []Issue
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		issues, err = ParseCSV(file)
+	} else {
+		issues, err = ParseJSON(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return issues, nil
+}
@@ -0,0 +1,55 @@
+package issue
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJSON(t *testing.T) {
+	issues, err := ParseJSON(strings.NewReader(`[
+		{"number": 12, "title": "Crash on start", "state": "open", "html_url": "https://github.com/o/r/issues/12"},
+		{"Key": "PROJ-42", "Summary": "Add login", "Status": "In Progress"},
+		{"id": 1296269, "number": 7, "title": "Both IDs"},
+		{"id": 84, "iid": 3, "title": "GitLab"}
+	]`))
+	require.NoError(t, err)
+	assert.Equal(t, []Issue{
+		{ID: "#12", Title: "Crash on start", State: "open", URL: "https://github.com/o/r/issues/12"},
+		{ID: "PROJ-42", Title: "Add login", State: "In Progress"},
+		{ID: "#7", Title: "Both IDs"},
+		{ID: "#3", Title: "GitLab"},
+	}, issues)
+
+	_, err = ParseJSON(strings.NewReader(`[{"title": "No ID"}]`))
+	assert.ErrorContains(t, err, "issue 0: missing id")
+
+	_, err = ParseJSON(strings.NewReader(`{}`))
+	assert.Error(t, err)
+}
+
+func TestParseCSV(t *testing.T) {
+	issues, err := ParseCSV(strings.NewReader("Issue key,Summary,Status\nPROJ-1,\"Fix it, now\",Done\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []Issue{{ID: "PROJ-1", Title: "Fix it, now", State: "Done"}}, issues)
+
+	_, err = ParseCSV(strings.NewReader("id,title\n1,\n"))
+	assert.ErrorContains(t, err, "line 2: #1: missing title")
+}
+
+func TestReadFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "issues.CSV")
+	require.NoError(t, os.WriteFile(path, []byte("id,title\n7,Seven\n"), 0o644))
+
+	issues, err := ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []Issue{{ID: "#7", Title: "Seven"}}, issues)
+
+	_, err = ReadFile(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/issue"
+)
+
+// Manage the issue index of a repository
+//
+// Returns the exit code
+func issues(args []string) int {
+	flags := flag.NewFlagSet("issues", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: git-lsp issues import [flags] file...\n\n")
+		fmt.Fprintf(flags.Output(), "Import issues from JSON or CSV exports of an issue tracker, so that references to them can be\n")
+		fmt.Fprintf(flags.Output(), "hovered and completed. Issues that are already in the index are replaced\n\n")
+		fmt.Fprintf(flags.Output(), "Each issue needs an ID (id, key, issue key, number or iid) and a title (title or summary), and may\n")
+		fmt.Fprintf(flags.Output(), "have a state (state or status) and a URL (url, html_url, web_url or link)\n\n")
+		flags.PrintDefaults()
+	}
+	repoDir := flags.String("repo", ".", "directory in the repository to import the issues for")
+
+	if len(args) == 0 || args[0] != "import" {
+		flags.Usage()
+		return 2
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	dir, err := filepath.Abs(*repoDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 2
+	}
+	// RepoRoot expects a file, so look for the repository of a file in the directory
+	root := helper.RepoRoot(filepath.Join(dir, config.FileName))
+	if root == "" {
+		fmt.Fprintf(os.Stderr, "error: %s is not in a git repository\n", dir)
+		return 2
+	}
+
+	imported := []issue.Issue{}
+	for _, path := range flags.Args() {
+		issues, err := issue.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			return 1
+		}
+		imported = append(imported, issues...)
+	}
+
+	stateDir, err := stateDirectory()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+
+	total, err := issue.Import(stateDir, root, imported)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Imported %d issues (%d in the index of %s)\n", len(imported), total, root)
+	return 0
+}
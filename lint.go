@@ -0,0 +1,111 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/external"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/eamonburns/git-lsp/report"
+)
+
+// Lint commit message files (or stdin) and write the diagnostics in the requested format
+//
+// Returns the exit code: 0 if there were no errors, 1 if there were errors, 2 on usage errors
+func lint(args []string) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: git-lsp lint [flags] [file...]\n\n")
+		fmt.Fprintf(flags.Output(), "Lint commit message files. If no files are given, or a file is '-', the message is read from stdin\n\n")
+		flags.PrintDefaults()
+	}
+
+	formatNames := make([]string, len(report.Formats))
+	for i, format := range report.Formats {
+		formatNames[i] = string(format)
+	}
+	formatFlag := flags.String("format", string(report.FormatText), "output format ("+strings.Join(formatNames, ", ")+")")
+	stdinName := flags.String("stdin-name", "COMMIT_EDITMSG", "file name to use in the output for a message read from stdin")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	format, err := report.ParseFormat(*formatFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 2
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+
+	files := []report.File{}
+	failed := false
+	for _, path := range paths {
+		var text []byte
+		var absPath string
+		if path == "-" {
+			path = *stdinName
+			absPath, err = filepath.Abs(*stdinName)
+			if err == nil {
+				text, err = io.ReadAll(os.Stdin)
+			}
+		} else {
+			absPath, err = filepath.Abs(path)
+			if err == nil {
+				text, err = os.ReadFile(path)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			return 2
+		}
+
+		root := helper.RepoRoot(absPath)
+		cfg, err := config.Load(root)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			return 2
+		}
+
+		msg := commit.ParseMessage(string(text))
+		diagnostics := commit.Check(&commit.Context{
+			Message: msg,
+			Repo:    commit.Repo{Root: root},
+			Config:  cfg,
+		})
+		diagnostics = append(diagnostics, external.RunAll(cfg.ExternalRules, root, string(text), msg)...)
+		diagnostics = commit.Suppress(msg, diagnostics, external.RuleIDs(cfg.ExternalRules))
+		for _, d := range diagnostics {
+			if d.ToLspDiagnostic().Severity == lsp.DiagnosticSeverityError {
+				failed = true
+			}
+		}
+
+		files = append(files, report.File{
+			Path:        path,
+			Diagnostics: diagnostics,
+			Lines:       msg.Lines,
+		})
+	}
+
+	if err := report.Write(os.Stdout, format, files); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 2
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
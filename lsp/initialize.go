@@ -10,6 +10,7 @@ type InitializeRequest struct {
 
 type InitializeRequestParams struct {
 	ClientInfo *ClientInfo `json:"clientInfo"`
+	RootURI    string      `json:"rootUri"`
 	// There is a ton more that could go here
 }
 
@@ -35,6 +36,19 @@ type ServerCapabilities struct {
 	DefinitionProvider bool           `json:"definitionProvider"`
 	CodeActionProvider bool           `json:"codeActionProvider"`
 	CompletionProvider map[string]any `json:"completionProvider"`
+
+	DocumentSymbolProvider bool `json:"documentSymbolProvider"`
+	FoldingRangeProvider   bool `json:"foldingRangeProvider"`
+
+	DocumentLinkProvider *DocumentLinkOptions `json:"documentLinkProvider,omitempty"`
+
+	SemanticTokensProvider *SemanticTokensOptions `json:"semanticTokensProvider,omitempty"`
+
+	Workspace *WorkspaceServerCapabilities `json:"workspace,omitempty"`
+}
+
+type WorkspaceServerCapabilities struct {
+	TextDocumentContent *TextDocumentContentOptions `json:"textDocumentContent,omitempty"`
 }
 
 type ServerInfo struct {
@@ -42,7 +56,7 @@ type ServerInfo struct {
 	Version string `json:"version"`
 }
 
-func NewInitializeResponse(id int) InitializeResponse {
+func NewInitializeResponse(id int, semanticTokensLegend SemanticTokensLegend, contentSchemes []string, triggerCharacters []string) InitializeResponse {
 	return InitializeResponse{
 		Response: Response{
 			RPC: "2.0",
@@ -54,7 +68,22 @@ func NewInitializeResponse(id int) InitializeResponse {
 				HoverProvider:      true,
 				DefinitionProvider: true,
 				CodeActionProvider: true,
-				CompletionProvider: make(map[string]any),
+				CompletionProvider: map[string]any{"triggerCharacters": triggerCharacters},
+
+				DocumentSymbolProvider: true,
+				FoldingRangeProvider:   true,
+
+				DocumentLinkProvider: &DocumentLinkOptions{},
+
+				SemanticTokensProvider: &SemanticTokensOptions{
+					Legend: semanticTokensLegend,
+					Range:  true,
+					Full:   SemanticTokensFullOptions{Delta: true},
+				},
+
+				Workspace: &WorkspaceServerCapabilities{
+					TextDocumentContent: &TextDocumentContentOptions{Schemes: contentSchemes},
+				},
 			},
 			ServerInfo: ServerInfo{
 				Name:    "git-lsp",
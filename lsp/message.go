@@ -14,9 +14,20 @@ type Response struct {
 	ID  *int   `json:"id,omitempty"`
 
 	// Result
-	// Error
+	Error *ResponseError `json:"error,omitempty"`
 }
 
+type ResponseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error codes
+const (
+	ErrorCodeInvalidParams = -32602
+	ErrorCodeRequestFailed = -32803
+)
+
 type Notification struct {
 	RPC    string `json:"jsonrpc"`
 	Method string `json:"method"`
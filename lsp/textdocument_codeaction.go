@@ -0,0 +1,37 @@
+package lsp
+
+type CodeActionRequest struct {
+	Request
+	Params CodeActionParams `json:"params"`
+}
+
+type CodeActionParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+	Context      CodeActionContext      `json:"context"`
+}
+
+type CodeActionContext struct {
+	// The diagnostics the client has for the requested range
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+type CodeActionResponse struct {
+	Response
+	Result []CodeAction `json:"result"`
+}
+
+type CodeAction struct {
+	Title       string         `json:"title"`
+	Kind        CodeActionKind `json:"kind,omitempty"`
+	Diagnostics []Diagnostic   `json:"diagnostics,omitempty"`
+	IsPreferred bool           `json:"isPreferred,omitempty"`
+	Edit        *WorkspaceEdit `json:"edit,omitempty"`
+}
+
+type CodeActionKind string
+
+const (
+	CodeActionKindQuickFix        CodeActionKind = "quickfix"
+	CodeActionKindRefactorRewrite CodeActionKind = "refactor.rewrite"
+)
@@ -19,8 +19,24 @@ type CompletionItem struct {
 	Detail        string             `json:"detail"`
 	Documentation string             `json:"documentation"`
 	Kind          CompletionItemKind `json:"kind"`
+
+	// Text used to sort and filter the items, instead of the label
+	SortText   string `json:"sortText,omitempty"`
+	FilterText string `json:"filterText,omitempty"`
+	// Edit to apply instead of inserting the label
+	TextEdit *TextEdit `json:"textEdit,omitempty"`
+	// Whether the new text of the edit is plain text or a snippet
+	InsertTextFormat InsertTextFormat `json:"insertTextFormat,omitempty"`
 }
 
+type InsertTextFormat int
+
+const (
+	InsertTextFormatPlainText InsertTextFormat = 1
+	// Text with tab stops and placeholders (e.g. "${1:type}: $0")
+	InsertTextFormatSnippet InsertTextFormat = 2
+)
+
 type CompletionItemKind int
 
 const (
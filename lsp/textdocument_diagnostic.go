@@ -1,5 +1,7 @@
 package lsp
 
+import "fmt"
+
 type PublishDiagnosticsNotification struct {
 	Notification
 	Params PublishDiagnosticsParams `json:"params"`
@@ -11,8 +13,56 @@ type PublishDiagnosticsParams struct {
 }
 
 type Diagnostic struct {
-	Range    Range  `json:"range"`
-	Severity int    `json:"severity"`
-	Source   string `json:"source"`
-	Message  string `json:"message"`
+	Range    Range              `json:"range"`
+	Severity DiagnosticSeverity `json:"severity"`
+	Source   string             `json:"source"`
+	Message  string             `json:"message"`
+
+	// ID of the rule that produced the diagnostic
+	Code            string           `json:"code,omitempty"`
+	CodeDescription *CodeDescription `json:"codeDescription,omitempty"`
+}
+
+type CodeDescription struct {
+	// URL to documentation about the diagnostic code
+	Href string `json:"href"`
+}
+
+type DiagnosticSeverity int
+
+const (
+	DiagnosticSeverityError DiagnosticSeverity = iota + 1
+	DiagnosticSeverityWarning
+	DiagnosticSeverityInformation
+	DiagnosticSeverityHint
+)
+
+func ParseDiagnosticSeverity(s string) (DiagnosticSeverity, error) {
+	switch s {
+	case "error":
+		return DiagnosticSeverityError, nil
+	case "warning":
+		return DiagnosticSeverityWarning, nil
+	case "information", "info":
+		return DiagnosticSeverityInformation, nil
+	case "hint":
+		return DiagnosticSeverityHint, nil
+	default:
+		return 0, fmt.Errorf("unknown severity '%s'", s)
+	}
+}
+
+func (self DiagnosticSeverity) String() string {
+	switch self {
+	case DiagnosticSeverityError:
+		return "error"
+	case DiagnosticSeverityWarning:
+		return "warning"
+	case DiagnosticSeverityInformation:
+		return "information"
+	case DiagnosticSeverityHint:
+		return "hint"
+	default:
+		return "unknown"
+	}
 }
@@ -0,0 +1,25 @@
+package lsp
+
+type DocumentLinkRequest struct {
+	Request
+	Params DocumentLinkParams `json:"params"`
+}
+
+type DocumentLinkParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+type DocumentLinkResponse struct {
+	Response
+	Result []DocumentLink `json:"result"`
+}
+
+type DocumentLink struct {
+	Range   Range  `json:"range"`
+	Target  string `json:"target,omitempty"`
+	Tooltip string `json:"tooltip,omitempty"`
+}
+
+type DocumentLinkOptions struct {
+	ResolveProvider bool `json:"resolveProvider"`
+}
@@ -0,0 +1,28 @@
+package lsp
+
+// Type definitions for "workspace/textDocumentContent" request (LSP 3.18), which is also served
+// as "git-lsp/textDocumentContent" for clients that don't support it yet
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification/#workspace_textDocumentContent
+
+type TextDocumentContentRequest struct {
+	Request
+	Params TextDocumentContentParams `json:"params"`
+}
+
+type TextDocumentContentParams struct {
+	URI string `json:"uri"`
+}
+
+type TextDocumentContentResponse struct {
+	Response
+	Result *TextDocumentContentResult `json:"result,omitempty"`
+}
+
+type TextDocumentContentResult struct {
+	Text string `json:"text"`
+}
+
+type TextDocumentContentOptions struct {
+	// URI schemes the server provides content for
+	Schemes []string `json:"schemes"`
+}
@@ -0,0 +1,69 @@
+-- Open git-lsp:// URIs (e.g. from go-to-definition on a commit hash) in read-only buffers
+--
+-- The content is requested from the git-lsp server with the "git-lsp/textDocumentContent"
+-- request, which is the same as "workspace/textDocumentContent" from LSP 3.18
+--
+-- Usage:
+-- ```
+-- require("git_lsp_content").setup()
+-- ```
+
+local M = {}
+
+local scheme = "git-lsp"
+
+-- Filetype of the content of a URI, based on its kind ("git-lsp://<kind>/<name>?...")
+local function filetype(uri)
+	local kind, name = uri:match("^" .. scheme .. "://([^/]+)/([^?]*)")
+	if kind == "show" then
+		return "git"
+	elseif kind == "message" then
+		return "gitcommit"
+	elseif kind == "object" then
+		-- "HEAD:path/to/file"
+		local path = name:match(":(.+)$")
+		return path and vim.filetype.match({ filename = vim.uri_decode(path) }) or ""
+	end
+	return ""
+end
+
+local function load(args)
+	local client = vim.lsp.get_clients({ name = "git-lsp" })[1]
+	if not client then
+		vim.notify("git-lsp is not running", vim.log.levels.ERROR)
+		return
+	end
+
+	local response, err = client:request_sync("git-lsp/textDocumentContent", { uri = args.match }, 5000, args.buf)
+	if not response then
+		vim.notify("git-lsp: " .. tostring(err), vim.log.levels.ERROR)
+		return
+	end
+	if response.err then
+		vim.notify("git-lsp: " .. response.err.message, vim.log.levels.ERROR)
+		return
+	end
+
+	local lines = vim.split(response.result.text, "\n", { plain = true })
+	if lines[#lines] == "" then
+		table.remove(lines)
+	end
+
+	vim.bo[args.buf].modifiable = true
+	vim.api.nvim_buf_set_lines(args.buf, 0, -1, false, lines)
+	vim.bo[args.buf].buftype = "nofile"
+	vim.bo[args.buf].swapfile = false
+	vim.bo[args.buf].modifiable = false
+	vim.bo[args.buf].readonly = true
+	vim.bo[args.buf].filetype = filetype(args.match)
+end
+
+function M.setup()
+	vim.api.nvim_create_autocmd("BufReadCmd", {
+		group = vim.api.nvim_create_augroup("git-lsp-content", { clear = true }),
+		pattern = scheme .. "://*",
+		callback = load,
+	})
+end
+
+return M
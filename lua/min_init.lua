@@ -28,12 +28,21 @@ end
 vim.lsp.config["git-lsp"] = {
 	cmd = { exe_path },
 
-	filetypes = { "gitcommit" },
+	-- "gitrebase" is the todo list of `git rebase -i` (git-rebase-todo)
+	filetypes = { "gitcommit", "gitrebase" },
 
 	root_markers = { ".git" },
 }
 vim.lsp.enable("git-lsp")
 
+-- Open git-lsp:// documents (e.g. `git show` of a commit hash)
+dofile(vim.fs.joinpath(lua_dir, "git_lsp_content.lua")).setup()
+
 ---------- Configure Neovim ----------
 
 vim.o.winborder = "rounded"
+
+-- Semantic token types and modifiers that are specific to git-lsp
+vim.api.nvim_set_hl(0, "@lsp.type.issue.gitcommit", { link = "@markup.link.label" })
+vim.api.nvim_set_hl(0, "@lsp.type.url.gitcommit", { link = "@markup.link.url" })
+vim.api.nvim_set_hl(0, "@lsp.mod.unknown.gitcommit", { link = "DiagnosticUnderlineWarn" })
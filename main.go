@@ -15,14 +15,23 @@ import (
 )
 
 func main() {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "error: %v", err)
-		os.Exit(1)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "lint":
+			os.Exit(lint(os.Args[2:]))
+		case "rules":
+			os.Exit(rules(os.Args[2:]))
+		case "issues":
+			os.Exit(issues(os.Args[2:]))
+		case "prepare":
+			os.Exit(prepare(os.Args[2:]))
+		case "commit":
+			os.Exit(composeCommit(os.Args[2:]))
+		}
 	}
 
-	stateDir := filepath.Join(homeDir, ".local", "state", "git-lsp")
-	if err := os.MkdirAll(stateDir, 0o755); err != nil {
+	stateDir, err := stateDirectory()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v", err)
 		os.Exit(1)
 	}
@@ -43,8 +52,21 @@ func main() {
 	scanner := bufio.NewScanner(os.Stdin)
 	scanner.Split(rpc.Split)
 
-	state := analysis.NewState()
+	state := analysis.NewState(stateDir)
 	writer := os.Stdout
+	// Called with the state locked, so it does not write at the same time as handleMessage
+	state.Publish = func(uri string, diagnostics []lsp.Diagnostic) {
+		writeResponse(writer, lsp.PublishDiagnosticsNotification{
+			Notification: lsp.Notification{
+				RPC:    "2.0",
+				Method: "textDocument/publishDiagnostics",
+			},
+			Params: lsp.PublishDiagnosticsParams{
+				URI:         uri,
+				Diagnostics: diagnostics,
+			},
+		})
+	}
 
 	for scanner.Scan() {
 		msg := scanner.Bytes()
@@ -54,11 +76,28 @@ func main() {
 			continue
 		}
 
-		handleMessage(writer, state, method, contents)
+		state.Lock()
+		handleMessage(writer, &state, method, contents)
+		state.Unlock()
 	}
 }
 
-func handleMessage(writer io.Writer, state analysis.State, method string, contents []byte) {
+// Directory where the server writes its log and the issue indexes, created if it does not exist
+func stateDirectory() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	stateDir := filepath.Join(homeDir, ".local", "state", "git-lsp")
+	if err := os.MkdirAll(stateDir, 0o755); err != nil {
+		return "", err
+	}
+
+	return stateDir, nil
+}
+
+func handleMessage(writer io.Writer, state *analysis.State, method string, contents []byte) {
 	logger := slog.With("method", method)
 	logger.Info("Recieved message")
 
@@ -76,7 +115,12 @@ func handleMessage(writer io.Writer, state analysis.State, method string, conten
 			"version", request.Params.ClientInfo.Version,
 		)
 
-		msg := lsp.NewInitializeResponse(request.ID)
+		msg := lsp.NewInitializeResponse(
+			request.ID,
+			analysis.SemanticTokensLegend,
+			[]string{analysis.VirtualScheme},
+			analysis.CompletionTriggerCharacters(request.Params.RootURI),
+		)
 		writeResponse(writer, msg)
 
 		logger.Info("Sent initialize response")
@@ -88,7 +132,7 @@ func handleMessage(writer io.Writer, state analysis.State, method string, conten
 		}
 
 		logger.Info("opened file", "uri", request.Params.TextDocument.URI)
-		diagnostics := state.OpenDocument(request.Params.TextDocument.URI, request.Params.TextDocument.Text)
+		diagnostics := state.OpenDocument(request.Params.TextDocument.URI, request.Params.TextDocument.LanguageId, request.Params.TextDocument.Version, request.Params.TextDocument.Text)
 
 		writeResponse(writer, lsp.PublishDiagnosticsNotification{
 			Notification: lsp.Notification{
@@ -110,7 +154,7 @@ func handleMessage(writer io.Writer, state analysis.State, method string, conten
 
 		logger.Info("changed file", "uri", request.Params.TextDocument.URI)
 		for _, change := range request.Params.ContentChanges {
-			diagnostics := state.UpdateDocument(request.Params.TextDocument.URI, change.Text)
+			diagnostics := state.UpdateDocument(request.Params.TextDocument.URI, request.Params.TextDocument.Version, change.Text)
 			writeResponse(writer, lsp.PublishDiagnosticsNotification{
 				Notification: lsp.Notification{
 					RPC:    "2.0",
@@ -134,6 +178,30 @@ func handleMessage(writer io.Writer, state analysis.State, method string, conten
 
 		response := state.Hover(request.ID, request.Params.URI, request.Params.Position)
 
+		writeResponse(writer, response)
+	case "textDocument/definition":
+		var request lsp.DefinitionRequest
+		if err := json.Unmarshal(contents, &request); err != nil {
+			logger.Error("unable to parse request", "error", err)
+			return
+		}
+
+		logger.Info("definition", "uri", request.Params.URI, "position", request.Params.Position)
+
+		response := state.Definition(request.ID, request.Params.URI, request.Params.Position)
+
+		writeResponse(writer, response)
+	case "workspace/textDocumentContent", "git-lsp/textDocumentContent":
+		var request lsp.TextDocumentContentRequest
+		if err := json.Unmarshal(contents, &request); err != nil {
+			logger.Error("unable to parse request", "error", err)
+			return
+		}
+
+		logger.Info("text document content", "uri", request.Params.URI)
+
+		response := state.TextDocumentContent(request.ID, request.Params.URI)
+
 		writeResponse(writer, response)
 	case "textDocument/completion":
 		var request lsp.CompletionRequest
@@ -146,6 +214,90 @@ func handleMessage(writer io.Writer, state analysis.State, method string, conten
 
 		response := state.TextDocumentCompletion(request.ID, request.Params.URI, request.Params.Position)
 
+		writeResponse(writer, response)
+	case "textDocument/codeAction":
+		var request lsp.CodeActionRequest
+		if err := json.Unmarshal(contents, &request); err != nil {
+			logger.Error("unable to parse request", "error", err)
+			return
+		}
+
+		logger.Info("code action", "uri", request.Params.TextDocument.URI, "range", request.Params.Range)
+
+		response := state.CodeAction(request.ID, request.Params.TextDocument.URI, request.Params.Range)
+
+		writeResponse(writer, response)
+	case "textDocument/documentSymbol":
+		var request lsp.DocumentSymbolRequest
+		if err := json.Unmarshal(contents, &request); err != nil {
+			logger.Error("unable to parse request", "error", err)
+			return
+		}
+
+		logger.Info("document symbol", "uri", request.Params.TextDocument.URI)
+
+		response := state.DocumentSymbol(request.ID, request.Params.TextDocument.URI)
+
+		writeResponse(writer, response)
+	case "textDocument/foldingRange":
+		var request lsp.FoldingRangeRequest
+		if err := json.Unmarshal(contents, &request); err != nil {
+			logger.Error("unable to parse request", "error", err)
+			return
+		}
+
+		logger.Info("folding range", "uri", request.Params.TextDocument.URI)
+
+		response := state.FoldingRange(request.ID, request.Params.TextDocument.URI)
+
+		writeResponse(writer, response)
+	case "textDocument/documentLink":
+		var request lsp.DocumentLinkRequest
+		if err := json.Unmarshal(contents, &request); err != nil {
+			logger.Error("unable to parse request", "error", err)
+			return
+		}
+
+		logger.Info("document link", "uri", request.Params.TextDocument.URI)
+
+		response := state.DocumentLink(request.ID, request.Params.TextDocument.URI)
+
+		writeResponse(writer, response)
+	case "textDocument/semanticTokens/full":
+		var request lsp.SemanticTokensRequest
+		if err := json.Unmarshal(contents, &request); err != nil {
+			logger.Error("unable to parse request", "error", err)
+			return
+		}
+
+		logger.Info("semantic tokens", "uri", request.Params.TextDocument.URI)
+
+		response := state.SemanticTokensFull(request.ID, request.Params.TextDocument.URI)
+
+		writeResponse(writer, response)
+	case "textDocument/semanticTokens/full/delta":
+		var request lsp.SemanticTokensDeltaRequest
+		if err := json.Unmarshal(contents, &request); err != nil {
+			logger.Error("unable to parse request", "error", err)
+			return
+		}
+
+		logger.Info("semantic tokens delta", "uri", request.Params.TextDocument.URI, "previousResultId", request.Params.PreviousResultID)
+
+		response := state.SemanticTokensFullDelta(request.ID, request.Params.TextDocument.URI, request.Params.PreviousResultID)
+
+		writeResponse(writer, response)
+	case "textDocument/semanticTokens/range":
+		var request lsp.SemanticTokensRangeRequest
+		if err := json.Unmarshal(contents, &request); err != nil {
+			logger.Error("unable to parse request", "error", err)
+			return
+		}
+
+		logger.Info("semantic tokens range", "uri", request.Params.TextDocument.URI, "range", request.Params.Range)
+
+		response := state.SemanticTokensRange(request.ID, request.Params.TextDocument.URI, request.Params.Range)
+
 		writeResponse(writer, response)
 	}
 }
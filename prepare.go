@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/config"
+	"github.com/eamonburns/git-lsp/git"
+	"github.com/eamonburns/git-lsp/internal/helper"
+)
+
+// Fill in a new commit message from the staged changes, as a prepare-commit-msg hook
+//
+// Returns the exit code. Problems with the repository only cause a warning, so that they do not
+// stop the commit
+func prepare(args []string) int {
+	flags := flag.NewFlagSet("prepare", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: git-lsp prepare file [source [commit]]\n\n")
+		fmt.Fprintf(flags.Output(), "Fill in a new commit message with a header inferred from the staged files, a Refs trailer with\n")
+		fmt.Fprintf(flags.Output(), "the ticket of the branch and a summary of the staged changes. Messages of merges, squashes,\n")
+		fmt.Fprintf(flags.Output(), "amends and `git commit -m` are left as they are\n\n")
+		fmt.Fprintf(flags.Output(), "To use it as a hook, add this to .git/hooks/prepare-commit-msg:\n\n")
+		fmt.Fprintf(flags.Output(), "    #!/bin/sh\n    exec git-lsp prepare \"$@\"\n")
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() < 1 || flags.NArg() > 3 {
+		flags.Usage()
+		return 2
+	}
+	path := flags.Arg(0)
+	source := flags.Arg(1)
+
+	text, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	root := helper.RepoRoot(absPath)
+	if root == "" {
+		fmt.Fprintf(os.Stderr, "warning: %s is not in a git repository\n", path)
+		return 0
+	}
+
+	cfg, err := config.Load(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		return 0
+	}
+	changes, err := git.StagedChanges(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		return 0
+	}
+
+	prepared, ok := commit.Prepare(commit.Repo{Root: root}, cfg, string(text), source, changes)
+	if !ok {
+		return 0
+	}
+	if err := os.WriteFile(path, []byte(prepared), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,115 @@
+package rebase
+
+import (
+	"fmt"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// Diagnostic error types
+//
+// These are not registered rules, because they check todo lists instead of commit messages, so
+// their diagnostics have their message and severity set
+const (
+	// Args: 0 = command
+	UnknownCommandError commit.DiagnosticType = "rebase/unknown-command"
+	// Args: 0 = command
+	MissingCommitError commit.DiagnosticType = "rebase/missing-commit"
+	// Args: 0 = commit
+	UnknownCommitError commit.DiagnosticType = "rebase/unknown-commit"
+	// Args: 0 = command
+	MissingArgumentError commit.DiagnosticType = "rebase/missing-argument"
+)
+
+// The commands that are replaced by each other when cycling
+var cycle = []string{"pick", "reword", "edit", "squash", "fixup", "drop"}
+
+// The other commands that take a commit, starting with the one after the command in the cycle
+func (self Line) Alternatives() []string {
+	command, ok := LookupCommand(self.Command)
+	if !ok || command.Argument != CommitArgument {
+		return nil
+	}
+
+	alternatives := []string{}
+	for i, name := range cycle {
+		if name != command.Name {
+			continue
+		}
+		for j := 1; j < len(cycle); j++ {
+			alternatives = append(alternatives, cycle[(i+j)%len(cycle)])
+		}
+	}
+	return alternatives
+}
+
+// Edit that replaces the command, keeping it abbreviated if it is
+func (self Line) ReplaceCommand(name string) lsp.TextEdit {
+	if self.IsShort() {
+		command, _ := LookupCommand(name)
+		name = command.Short
+	}
+	return lsp.TextEdit{Range: self.CommandRange, NewText: name}
+}
+
+func commandNames() []string {
+	names := []string{}
+	for _, command := range Commands {
+		names = append(names, command.Name)
+	}
+	return names
+}
+
+// Check the commands of the todo list. Commits are looked up with exists, unless it is nil
+func Check(todo *Todo, exists func(rev string) bool) []commit.Diagnostic {
+	diagnostics := []commit.Diagnostic{}
+	for _, line := range todo.Commands {
+		command, ok := LookupCommand(line.Command)
+		if !ok {
+			diagnostic := commit.Diagnostic{
+				Range:    line.CommandRange,
+				Type:     UnknownCommandError,
+				Args:     []string{line.Command},
+				Message:  fmt.Sprintf("Unknown command '%s'", line.Command),
+				Severity: lsp.DiagnosticSeverityError,
+			}
+			for _, suggestion := range commit.Suggestions(line.Command, commandNames()) {
+				diagnostic.Fixes = append(diagnostic.Fixes, commit.Fix{
+					Title: fmt.Sprintf("Replace with '%s'", suggestion),
+					Edits: []lsp.TextEdit{{Range: line.CommandRange, NewText: suggestion}},
+				})
+			}
+			diagnostics = append(diagnostics, diagnostic)
+			continue
+		}
+
+		switch {
+		case command.Argument == CommitArgument && line.Commit == "":
+			diagnostics = append(diagnostics, commit.Diagnostic{
+				Range:    line.CommandRange,
+				Type:     MissingCommitError,
+				Args:     []string{line.Command},
+				Message:  fmt.Sprintf("'%s' needs a commit", command.Name),
+				Severity: lsp.DiagnosticSeverityError,
+			})
+		case line.Commit != "" && exists != nil && !exists(line.Commit):
+			diagnostics = append(diagnostics, commit.Diagnostic{
+				Range:    line.CommitRange,
+				Type:     UnknownCommitError,
+				Args:     []string{line.Commit},
+				Message:  fmt.Sprintf("Unknown commit '%s'", line.Commit),
+				Severity: lsp.DiagnosticSeverityError,
+			})
+		case command.Argument != NoArgument && command.Argument != CommitArgument && line.Argument == "":
+			diagnostics = append(diagnostics, commit.Diagnostic{
+				Range:    line.CommandRange,
+				Type:     MissingArgumentError,
+				Args:     []string{line.Command},
+				Message:  fmt.Sprintf("'%s' needs an argument", command.Name),
+				Severity: lsp.DiagnosticSeverityError,
+			})
+		}
+	}
+	return diagnostics
+}
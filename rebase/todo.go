@@ -0,0 +1,158 @@
+// Package rebase parses and checks the todo list of an interactive rebase ("git-rebase-todo")
+package rebase
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// Name of the file that `git rebase -i` opens in the editor
+const FileName = "git-rebase-todo"
+
+// What follows the name of a command
+type Argument int
+
+const (
+	// break
+	NoArgument Argument = iota
+	// pick <commit> [<subject>]
+	CommitArgument
+	// exec <command>
+	ShellArgument
+	// label <label>, reset <label>
+	LabelArgument
+	// update-ref <ref>
+	RefArgument
+	// merge [-C <commit> | -c <commit>] <label> [# <oneline>]
+	MergeArgument
+)
+
+type Command struct {
+	Name string
+	// Abbreviation (e.g. "p" for "pick")
+	Short    string
+	Argument Argument
+	// What the command does, as in the help at the end of the todo list
+	Description string
+}
+
+// The commands of the todo list, in the order of the help that git writes
+var Commands = []Command{
+	{"pick", "p", CommitArgument, "use commit"},
+	{"reword", "r", CommitArgument, "use commit, but edit the commit message"},
+	{"edit", "e", CommitArgument, "use commit, but stop for amending"},
+	{"squash", "s", CommitArgument, "use commit, but meld into previous commit"},
+	{"fixup", "f", CommitArgument, "like \"squash\" but keep only the previous commit's log message, unless -C is used"},
+	{"exec", "x", ShellArgument, "run command (the rest of the line) using shell"},
+	{"break", "b", NoArgument, "stop here (continue rebase later with 'git rebase --continue')"},
+	{"drop", "d", CommitArgument, "remove commit"},
+	{"label", "l", LabelArgument, "label current HEAD with a name"},
+	{"reset", "t", LabelArgument, "reset HEAD to a label"},
+	{"merge", "m", MergeArgument, "create a merge commit using the original merge commit's message"},
+	{"update-ref", "u", RefArgument, "track a placeholder for the ref to be updated to this position in the new commits"},
+}
+
+// The command with the name or abbreviation, or false if there is none
+func LookupCommand(name string) (Command, bool) {
+	i := slices.IndexFunc(Commands, func(command Command) bool {
+		return command.Name == name || command.Short == name
+	})
+	if i == -1 {
+		return Command{}, false
+	}
+	return Commands[i], true
+}
+
+// A line of the todo list with a command
+type Line struct {
+	Line int
+	// The command as it is written (e.g. "p" or "pick")
+	Command      string
+	CommandRange lsp.Range
+	// Commit of the command, or of the -C/-c option of fixup and merge. "" if there is none
+	Commit      string
+	CommitRange lsp.Range
+	// The rest of the line: the subject of a commit, or the argument of other commands
+	Argument      string
+	ArgumentRange lsp.Range
+}
+
+// Whether the command is written as an abbreviation
+func (self Line) IsShort() bool {
+	command, ok := LookupCommand(self.Command)
+	return ok && command.Short == self.Command
+}
+
+type Todo struct {
+	Lines []string
+	// The lines with commands. Empty lines and comments are left out
+	Commands []Line
+}
+
+// The next field of the line, starting at start. Returns the range of the field, or an empty
+// range at the end of the line if there are no more fields
+func nextField(text string, start int) (int, int) {
+	for start < len(text) && (text[start] == ' ' || text[start] == '\t') {
+		start++
+	}
+	end := start
+	for end < len(text) && text[end] != ' ' && text[end] != '\t' {
+		end++
+	}
+	return start, end
+}
+
+func Parse(text string) *Todo {
+	todo := &Todo{Lines: strings.Split(text, "\n")}
+
+	for i, text := range todo.Lines {
+		text = strings.TrimRight(text, "\r")
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(strings.TrimSpace(text), commit.CommentChar) {
+			continue
+		}
+
+		start, end := nextField(text, 0)
+		line := Line{
+			Line:         i,
+			Command:      text[start:end],
+			CommandRange: helper.LineRange(i, start, end),
+		}
+
+		command, _ := LookupCommand(line.Command)
+		start, end = nextField(text, end)
+		if command.Argument == CommitArgument || command.Argument == MergeArgument {
+			// fixup -C <commit>, merge -c <commit> <label>
+			option := text[start:end]
+			if option == "-C" || option == "-c" {
+				start, end = nextField(text, end)
+			}
+			if command.Argument == CommitArgument || option == "-C" || option == "-c" {
+				line.Commit = text[start:end]
+				line.CommitRange = helper.LineRange(i, start, end)
+				start, end = nextField(text, end)
+			}
+		}
+
+		rest := strings.TrimSpace(text[start:])
+		line.Argument = rest
+		line.ArgumentRange = helper.LineRange(i, start, start+len(rest))
+		todo.Commands = append(todo.Commands, line)
+	}
+
+	return todo
+}
+
+// The command on the line, or false if the line has no command
+func (self *Todo) LineAt(line int) (Line, bool) {
+	i := slices.IndexFunc(self.Commands, func(l Line) bool {
+		return l.Line == line
+	})
+	if i == -1 {
+		return Line{}, false
+	}
+	return self.Commands[i], true
+}
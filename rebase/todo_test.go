@@ -0,0 +1,104 @@
+package rebase
+
+import (
+	"testing"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/eamonburns/git-lsp/lsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	todo := Parse("pick 1a2b3c4 feat: add x\n" +
+		"\n" +
+		"f -C 5d6e7f8 fix: y\n" +
+		"exec make test\n" +
+		"merge -C 9a8b7c6 topic # Merge branch 'topic'\n" +
+		"break\n" +
+		"# Rebase 0000000..1a2b3c4 onto 0000000 (3 commands)\n")
+
+	require.Len(t, todo.Commands, 5)
+	assert.Equal(t, Line{
+		Line:          0,
+		Command:       "pick",
+		CommandRange:  helper.LineRange(0, 0, 4),
+		Commit:        "1a2b3c4",
+		CommitRange:   helper.LineRange(0, 5, 12),
+		Argument:      "feat: add x",
+		ArgumentRange: helper.LineRange(0, 13, 24),
+	}, todo.Commands[0])
+
+	assert.Equal(t, "5d6e7f8", todo.Commands[1].Commit)
+	assert.Equal(t, helper.LineRange(2, 5, 12), todo.Commands[1].CommitRange)
+	assert.True(t, todo.Commands[1].IsShort())
+
+	assert.Equal(t, "", todo.Commands[2].Commit)
+	assert.Equal(t, "make test", todo.Commands[2].Argument)
+
+	assert.Equal(t, "9a8b7c6", todo.Commands[3].Commit)
+	assert.Equal(t, "topic # Merge branch 'topic'", todo.Commands[3].Argument)
+
+	assert.Equal(t, "break", todo.Commands[4].Command)
+
+	line, ok := todo.LineAt(3)
+	assert.True(t, ok)
+	assert.Equal(t, "exec", line.Command)
+	_, ok = todo.LineAt(1)
+	assert.False(t, ok)
+}
+
+func TestCheck(t *testing.T) {
+	todo := Parse("pikc 1a2b3c4 x\npick\nreword 0000000 y\nlabel\nbreak\n")
+	diagnostics := Check(todo, func(rev string) bool {
+		return rev == "1a2b3c4"
+	})
+
+	assert.Equal(t, []commit.Diagnostic{
+		{
+			Range:    helper.LineRange(0, 0, 4),
+			Type:     UnknownCommandError,
+			Args:     []string{"pikc"},
+			Message:  "Unknown command 'pikc'",
+			Severity: lsp.DiagnosticSeverityError,
+			Fixes: []commit.Fix{{
+				Title: "Replace with 'pick'",
+				Edits: []lsp.TextEdit{{Range: helper.LineRange(0, 0, 4), NewText: "pick"}},
+			}},
+		},
+		{
+			Range:    helper.LineRange(1, 0, 4),
+			Type:     MissingCommitError,
+			Args:     []string{"pick"},
+			Message:  "'pick' needs a commit",
+			Severity: lsp.DiagnosticSeverityError,
+		},
+		{
+			Range:    helper.LineRange(2, 7, 14),
+			Type:     UnknownCommitError,
+			Args:     []string{"0000000"},
+			Message:  "Unknown commit '0000000'",
+			Severity: lsp.DiagnosticSeverityError,
+		},
+		{
+			Range:    helper.LineRange(3, 0, 5),
+			Type:     MissingArgumentError,
+			Args:     []string{"label"},
+			Message:  "'label' needs an argument",
+			Severity: lsp.DiagnosticSeverityError,
+		},
+	}, diagnostics)
+
+	assert.Len(t, Check(todo, nil), 3)
+}
+
+func TestAlternatives(t *testing.T) {
+	todo := Parse("pick 1a2b3c4 x\ns 1a2b3c4 y\nexec true")
+
+	assert.Equal(t, []string{"reword", "edit", "squash", "fixup", "drop"}, todo.Commands[0].Alternatives())
+	assert.Equal(t, []string{"fixup", "drop", "pick", "reword", "edit"}, todo.Commands[1].Alternatives())
+	assert.Empty(t, todo.Commands[2].Alternatives())
+
+	assert.Equal(t, lsp.TextEdit{Range: helper.LineRange(1, 0, 1), NewText: "f"}, todo.Commands[1].ReplaceCommand("fixup"))
+}
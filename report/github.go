@@ -0,0 +1,47 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// GitHub Actions workflow commands: https://docs.github.com/en/actions/reference/workflow-commands-for-github-actions
+
+func githubCommand(severity lsp.DiagnosticSeverity) string {
+	switch severity {
+	case lsp.DiagnosticSeverityError:
+		return "error"
+	case lsp.DiagnosticSeverityWarning:
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+var githubDataEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+var githubPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+
+func writeGitHub(w io.Writer, files []File) error {
+	for _, result := range results(files) {
+		_, err := fmt.Fprintf(
+			w,
+			"::%s file=%s,line=%d,col=%d,endLine=%d,endColumn=%d,title=%s::%s\n",
+			githubCommand(result.Severity),
+			githubPropertyEscaper.Replace(result.Path),
+			result.Range.Start.Line+1,
+			result.Range.Start.Character+1,
+			result.Range.End.Line+1,
+			result.Range.End.Character+1,
+			githubPropertyEscaper.Replace(result.RuleID),
+			githubDataEscaper.Replace(result.Message),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// GitLab Code Quality: https://docs.gitlab.com/ci/testing/code_quality/#code-quality-report-format
+
+type gitlabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    gitlabLocation `json:"location"`
+}
+
+type gitlabLocation struct {
+	Path  string      `json:"path"`
+	Lines gitlabLines `json:"lines"`
+}
+
+type gitlabLines struct {
+	Begin int `json:"begin"`
+}
+
+func gitlabSeverity(severity lsp.DiagnosticSeverity) string {
+	switch severity {
+	case lsp.DiagnosticSeverityError:
+		return "major"
+	case lsp.DiagnosticSeverityWarning:
+		return "minor"
+	default:
+		return "info"
+	}
+}
+
+func writeGitLab(w io.Writer, files []File) error {
+	issues := []gitlabIssue{}
+
+	for _, result := range results(files) {
+		// The fingerprint must be unique per issue, and stable between runs
+		hash := sha256.Sum256(fmt.Appendf(
+			nil,
+			"%s:%s:%d:%d:%s",
+			result.Path,
+			result.RuleID,
+			result.Range.Start.Line,
+			result.Range.Start.Character,
+			result.Message,
+		))
+
+		issues = append(issues, gitlabIssue{
+			Description: result.Message,
+			CheckName:   result.RuleID,
+			Fingerprint: hex.EncodeToString(hash[:]),
+			Severity:    gitlabSeverity(result.Severity),
+			Location: gitlabLocation{
+				Path:  result.Path,
+				Lines: gitlabLines{Begin: result.Range.Start.Line + 1},
+			},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(issues)
+}
@@ -0,0 +1,25 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+)
+
+type jsonResult struct {
+	Result
+	Severity string `json:"severity"`
+}
+
+func writeJSON(w io.Writer, files []File) error {
+	output := []jsonResult{}
+	for _, result := range results(files) {
+		output = append(output, jsonResult{
+			Result:   result,
+			Severity: result.Severity.String(),
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
@@ -0,0 +1,91 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// JUnit XML. There is no formal specification, but this is the subset that is understood by most CI
+// systems: one test suite per file, and one failed test case per diagnostic
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func writeJUnit(w io.Writer, files []File) error {
+	suites := junitTestSuites{Name: "git-lsp"}
+
+	for _, file := range files {
+		suite := junitTestSuite{Name: file.Path}
+
+		for _, result := range results([]File{file}) {
+			suite.TestCases = append(suite.TestCases, junitTestCase{
+				Name:      fmt.Sprintf("%s:%d:%d", result.RuleID, result.Range.Start.Line+1, result.Range.Start.Character+1),
+				ClassName: file.Path,
+				Failure: &junitFailure{
+					Message: result.Message,
+					Type:    result.Severity.String(),
+					Text: fmt.Sprintf(
+						"%s:%d:%d: %s [%s]",
+						result.Path,
+						result.Range.Start.Line+1,
+						result.Range.Start.Character+1,
+						result.Message,
+						result.RuleID,
+					),
+				},
+			})
+			suite.Failures++
+		}
+
+		if len(suite.TestCases) == 0 {
+			// Report a passing test case so that clean files still show up
+			suite.TestCases = append(suite.TestCases, junitTestCase{
+				Name:      "git-lsp",
+				ClassName: file.Path,
+			})
+		}
+
+		suite.Tests = len(suite.TestCases)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,99 @@
+// Package report writes lint results in formats that can be consumed by other tools (CI systems,
+// code scanning, test reporters)
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+type Format string
+
+const (
+	// Human readable "file:line:column: severity: message [rule]" lines
+	FormatText Format = "text"
+	// JSON array with one object per diagnostic
+	FormatJSON Format = "json"
+	// SARIF 2.1.0 log (e.g. for GitHub code scanning uploads)
+	FormatSARIF Format = "sarif"
+	// GitHub Actions workflow commands (e.g. "::error file=...::message")
+	FormatGitHub Format = "github"
+	// GitLab Code Quality report
+	FormatGitLab Format = "gitlab"
+	// JUnit XML test report
+	FormatJUnit Format = "junit"
+)
+
+var Formats = []Format{FormatText, FormatJSON, FormatSARIF, FormatGitHub, FormatGitLab, FormatJUnit}
+
+func ParseFormat(s string) (Format, error) {
+	for _, format := range Formats {
+		if string(format) == strings.ToLower(s) {
+			return format, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown format '%s'", s)
+}
+
+// The lint results of a single commit message file
+type File struct {
+	// Path of the file, as it should appear in the report
+	Path        string
+	Diagnostics []commit.Diagnostic
+	// Lines of the file, to convert the byte offsets of the diagnostics for formats that count
+	// columns differently (e.g. SARIF). Without them, byte offsets are used
+	Lines []string
+}
+
+// A single diagnostic with everything needed to report it. All formats are generated from this
+type Result struct {
+	Path     string                 `json:"path"`
+	RuleID   string                 `json:"ruleId"`
+	Severity lsp.DiagnosticSeverity `json:"-"`
+	Message  string                 `json:"message"`
+	Range    lsp.Range              `json:"range"`
+}
+
+func results(files []File) []Result {
+	results := []Result{}
+
+	for _, file := range files {
+		for _, d := range file.Diagnostics {
+			lspDiagnostic := d.ToLspDiagnostic()
+			results = append(results, Result{
+				Path:     file.Path,
+				RuleID:   string(d.Type),
+				Severity: lspDiagnostic.Severity,
+				Message:  lspDiagnostic.Message,
+				Range:    d.Range,
+			})
+		}
+	}
+
+	return results
+}
+
+// Write the diagnostics of all files to w in the given format
+func Write(w io.Writer, format Format, files []File) error {
+	switch format {
+	case FormatText:
+		return writeText(w, files)
+	case FormatJSON:
+		return writeJSON(w, files)
+	case FormatSARIF:
+		return writeSARIF(w, files)
+	case FormatGitHub:
+		return writeGitHub(w, files)
+	case FormatGitLab:
+		return writeGitLab(w, files)
+	case FormatJUnit:
+		return writeJUnit(w, files)
+	default:
+		return fmt.Errorf("unknown format '%s'", format)
+	}
+}
@@ -0,0 +1,131 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/internal/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testFiles = []File{
+	{
+		Path: "COMMIT_EDITMSG",
+		Diagnostics: []commit.Diagnostic{
+			{
+				Range: helper.LineRange(0, 11, 14),
+				Type:  commit.ExtraCharactersAfterScopeError,
+				Args:  []string{"bla"},
+			},
+		},
+	},
+	{
+		Path:        "clean.txt",
+		Diagnostics: []commit.Diagnostic{},
+	},
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("SARIF")
+	require.NoError(t, err)
+	assert.Equal(t, FormatSARIF, format)
+
+	_, err = ParseFormat("yaml")
+	require.Error(t, err)
+}
+
+func TestWriteText(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatText, testFiles))
+	assert.Equal(t, "COMMIT_EDITMSG:1:12: error: Extra characters after scope: 'bla' [header/extra-characters-after-scope]\n", buf.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatJSON, testFiles))
+
+	var output []map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &output))
+	require.Len(t, output, 1)
+	assert.Equal(t, "COMMIT_EDITMSG", output[0]["path"])
+	assert.Equal(t, "header/extra-characters-after-scope", output[0]["ruleId"])
+	assert.Equal(t, "error", output[0]["severity"])
+	assert.Equal(t, map[string]any{
+		"start": map[string]any{"line": 0.0, "character": 11.0},
+		"end":   map[string]any{"line": 0.0, "character": 14.0},
+	}, output[0]["range"])
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatSARIF, testFiles))
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	require.Len(t, log.Runs[0].Tool.Driver.Rules, 1)
+	assert.Equal(t, "header/extra-characters-after-scope", log.Runs[0].Tool.Driver.Rules[0].ID)
+	assert.NotEmpty(t, log.Runs[0].Tool.Driver.Rules[0].HelpURI)
+	require.Len(t, log.Runs[0].Results, 1)
+	assert.Equal(t, "error", log.Runs[0].Results[0].Level)
+	assert.Equal(t, sarifRegion{StartLine: 1, StartColumn: 12, EndLine: 1, EndColumn: 15}, log.Runs[0].Results[0].Locations[0].PhysicalLocation.Region)
+
+	// Columns count UTF-16 code units, not bytes ("é" is 2 bytes, "😀" is 4 bytes and 2 code units)
+	buf.Reset()
+	require.NoError(t, Write(&buf, FormatSARIF, []File{
+		{
+			Path: "COMMIT_EDITMSG",
+			Diagnostics: []commit.Diagnostic{
+				{Range: helper.LineRange(0, 12, 15), Type: commit.ExtraCharactersAfterScopeError, Args: []string{"bla"}},
+			},
+			Lines: []string{"feat(é😀)bla: x"},
+		},
+	}))
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, sarifRegion{StartLine: 1, StartColumn: 10, EndLine: 1, EndColumn: 13}, log.Runs[0].Results[0].Locations[0].PhysicalLocation.Region)
+}
+
+func TestWriteGitHub(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatGitHub, []File{
+		{
+			Path: "a,b:c",
+			Diagnostics: []commit.Diagnostic{
+				{Range: helper.LineRange(0, 0, 4), Type: commit.NoTypeScopeError},
+			},
+		},
+	}))
+	assert.Equal(t, "::error file=a%2Cb%3Ac,line=1,col=1,endLine=1,endColumn=5,title=header/no-type-scope::No type/scope in header line\n", buf.String())
+}
+
+func TestWriteGitLab(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatGitLab, testFiles))
+
+	var issues []gitlabIssue
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &issues))
+	require.Len(t, issues, 1)
+	assert.Equal(t, "header/extra-characters-after-scope", issues[0].CheckName)
+	assert.Equal(t, "major", issues[0].Severity)
+	assert.Equal(t, 1, issues[0].Location.Lines.Begin)
+	assert.Len(t, issues[0].Fingerprint, 64)
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatJUnit, testFiles))
+
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+	assert.Equal(t, 2, suites.Tests)
+	assert.Equal(t, 1, suites.Failures)
+	require.Len(t, suites.Suites, 2)
+	require.NotNil(t, suites.Suites[0].TestCases[0].Failure)
+	assert.Equal(t, "Extra characters after scope: 'bla'", suites.Suites[0].TestCases[0].Failure.Message)
+	assert.Nil(t, suites.Suites[1].TestCases[0].Failure)
+}
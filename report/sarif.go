@@ -0,0 +1,163 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"slices"
+	"unicode/utf16"
+
+	"github.com/eamonburns/git-lsp/commit"
+	"github.com/eamonburns/git-lsp/lsp"
+)
+
+// SARIF 2.1.0: https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	ShortDescription *sarifMessage `json:"shortDescription,omitempty"`
+	HelpURI          string        `json:"helpUri,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	// SARIF lines and columns are 1-based
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+func sarifLevel(severity lsp.DiagnosticSeverity) string {
+	switch severity {
+	case lsp.DiagnosticSeverityError:
+		return "error"
+	case lsp.DiagnosticSeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// SARIF columns count UTF-16 code units by default (the "columnKind" of the run), while the
+// positions of the diagnostics are byte offsets
+func sarifColumn(lines []string, position lsp.Position) int {
+	if position.Line >= len(lines) {
+		return position.Character + 1
+	}
+
+	line := lines[position.Line]
+	column := 1
+	for i, r := range line {
+		if i >= position.Character {
+			break
+		}
+		column += utf16.RuneLen(r)
+	}
+	return column
+}
+
+func writeSARIF(w io.Writer, files []File) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "git-lsp",
+				InformationURI: "https://github.com/eamonburns/git-lsp",
+				Rules:          []sarifRule{},
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	ruleIDs := []string{}
+	for _, file := range files {
+		for _, result := range results([]File{file}) {
+			if !slices.Contains(ruleIDs, result.RuleID) {
+				ruleIDs = append(ruleIDs, result.RuleID)
+			}
+
+			run.Results = append(run.Results, sarifResult{
+				RuleID:  result.RuleID,
+				Level:   sarifLevel(result.Severity),
+				Message: sarifMessage{Text: result.Message},
+				Locations: []sarifLocation{
+					{
+						PhysicalLocation: sarifPhysicalLocation{
+							ArtifactLocation: sarifArtifactLocation{URI: result.Path},
+							Region: sarifRegion{
+								StartLine:   result.Range.Start.Line + 1,
+								StartColumn: sarifColumn(file.Lines, result.Range.Start),
+								EndLine:     result.Range.End.Line + 1,
+								EndColumn:   sarifColumn(file.Lines, result.Range.End),
+							},
+						},
+					},
+				},
+			})
+		}
+	}
+
+	slices.Sort(ruleIDs)
+	for _, id := range ruleIDs {
+		rule := sarifRule{ID: id}
+		if info, ok := commit.LookupRule(commit.DiagnosticType(id)); ok {
+			rule.ShortDescription = &sarifMessage{Text: info.Description}
+			rule.HelpURI = info.DocURL
+		}
+
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
@@ -0,0 +1,26 @@
+package report
+
+import (
+	"fmt"
+	"io"
+)
+
+func writeText(w io.Writer, files []File) error {
+	for _, result := range results(files) {
+		_, err := fmt.Fprintf(
+			w,
+			"%s:%d:%d: %s: %s [%s]\n",
+			result.Path,
+			result.Range.Start.Line+1,
+			result.Range.Start.Character+1,
+			result.Severity,
+			result.Message,
+			result.RuleID,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
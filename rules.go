@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/eamonburns/git-lsp/commit"
+)
+
+// List all known rules
+//
+// Returns the exit code
+func rules(args []string) int {
+	flags := flag.NewFlagSet("rules", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: git-lsp rules\n\n")
+		fmt.Fprintf(flags.Output(), "List all rules, with their ID, default severity and description\n")
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "ID\tSEVERITY\tDESCRIPTION")
+	for _, rule := range commit.Rules() {
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", rule.ID, rule.Severity, rule.Description)
+		if rule.DocURL != "" {
+			fmt.Fprintf(writer, "\t\t%s\n", rule.DocURL)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}